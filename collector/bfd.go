@@ -3,11 +3,30 @@ package collector
 import (
 	"encoding/json"
 	"log/slog"
+	"strconv"
 
+	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-var bfdSubsystem = "bfd"
+var (
+	bfdSubsystem    = "bfd"
+	bfdPeerMultihop = kingpin.Flag("collector.bfd.peer-multihop", "Adds whether the peer is a multihop peer as a label. (default: disabled).").Default("False").Bool()
+	bfdPeerProfile  = kingpin.Flag("collector.bfd.peer-profile", "Adds the peer's BFD profile name as a label. (default: disabled).").Default("False").Bool()
+
+	// bfdDiagnostics maps the diagnostic strings of bfdd to the diagnostic codes of RFC 5880.
+	bfdDiagnostics = map[string]float64{
+		"ok":                             0,
+		"control detection time expired": 1,
+		"echo function failed":           2,
+		"neighbor signaled session down": 3,
+		"forwarding plane reset":         4,
+		"path down":                      5,
+		"concatenated path down":         6,
+		"administratively down":          7,
+		"reverse concatenated path down": 8,
+	}
+)
 
 func init() {
 	registerCollector(bfdSubsystem, enabledByDefault, NewBFDCollector)
@@ -16,20 +35,56 @@ func init() {
 type bfdCollector struct {
 	logger       *slog.Logger
 	descriptions map[string]*prometheus.Desc
+	peerStates   *stateTracker
 }
 
 // NewBFDCollector collects BFD metrics, implemented as per the Collector interface.
 func NewBFDCollector(logger *slog.Logger) (Collector, error) {
-	return &bfdCollector{logger: logger, descriptions: getBFDDesc()}, nil
+	return &bfdCollector{logger: logger, descriptions: getBFDDesc(), peerStates: newStateTracker(bfdSubsystem, bfdSubsystem, "peer", getBFDPeerLabels())}, nil
+}
+
+// getBFDPeerLabels returns the labels of per-peer metrics, which depend on the optional peer labels enabled.
+func getBFDPeerLabels() []string {
+	peerLabels := []string{"local", "peer", "iface", "vrf"}
+
+	if *bfdPeerMultihop {
+		peerLabels = append(peerLabels, "multihop")
+	}
+
+	if *bfdPeerProfile {
+		peerLabels = append(peerLabels, "profile")
+	}
+	return peerLabels
 }
 
 func getBFDDesc() map[string]*prometheus.Desc {
 	countLabels := []string{}
-	peerLabels := []string{"local", "peer", "iface", "vrf"}
+	peerLabels := getBFDPeerLabels()
+
 	return map[string]*prometheus.Desc{
 		"bfdPeerCount":  colPromDesc(bfdSubsystem, "peer_count", "Number of peers detected.", countLabels),
 		"bfdPeerUptime": colPromDesc(bfdSubsystem, "peer_uptime", "Uptime of bfd peer in seconds", peerLabels),
 		"bfdPeerState":  colPromDesc(bfdSubsystem, "peer_state", "State of the bfd peer (1 = Up, 0 = Down).", peerLabels),
+
+		"receiveInterval":        colPromDesc(bfdSubsystem, "peer_receive_interval_seconds", "Required minimum receive interval configured for the bfd peer.", peerLabels),
+		"transmitInterval":       colPromDesc(bfdSubsystem, "peer_transmit_interval_seconds", "Desired minimum transmit interval configured for the bfd peer.", peerLabels),
+		"echoInterval":           colPromDesc(bfdSubsystem, "peer_echo_interval_seconds", "Echo interval configured for the bfd peer.", peerLabels),
+		"remoteReceiveInterval":  colPromDesc(bfdSubsystem, "peer_remote_receive_interval_seconds", "Receive interval advertised by the bfd peer.", peerLabels),
+		"remoteTransmitInterval": colPromDesc(bfdSubsystem, "peer_remote_transmit_interval_seconds", "Transmit interval advertised by the bfd peer.", peerLabels),
+		"remoteEchoInterval":     colPromDesc(bfdSubsystem, "peer_remote_echo_interval_seconds", "Echo interval advertised by the bfd peer.", peerLabels),
+		"detectMultiplier":       colPromDesc(bfdSubsystem, "peer_detect_multiplier", "Detection multiplier of the bfd session.", peerLabels),
+		"remoteDetectMultiplier": colPromDesc(bfdSubsystem, "peer_remote_detect_multiplier", "Detection multiplier advertised by the bfd peer.", peerLabels),
+		"detectionTime":          colPromDesc(bfdSubsystem, "peer_detection_time_seconds", "Detection time of the bfd session as per RFC 5880 section 6.8.4, computed as the remote detection multiplier times the greater of the local receive interval and the remote transmit interval.", peerLabels),
+		"remoteDetectionTime":    colPromDesc(bfdSubsystem, "peer_remote_detection_time_seconds", "Detection time of the bfd peer, computed as the local detection multiplier times the greater of the remote receive interval and the local transmit interval.", peerLabels),
+		"diagnostic":             colPromDesc(bfdSubsystem, "peer_diagnostic", "Local diagnostic code of the bfd session as per RFC 5880 (0 = No Diagnostic, 1 = Control Detection Time Expired, 2 = Echo Function Failed, 3 = Neighbor Signaled Session Down, 4 = Forwarding Plane Reset, 5 = Path Down, 6 = Concatenated Path Down, 7 = Administratively Down, 8 = Reverse Concatenated Path Down).", peerLabels),
+		"remoteDiagnostic":       colPromDesc(bfdSubsystem, "peer_remote_diagnostic", "Diagnostic code of the bfd session reported by the peer as per RFC 5880, see frr_bfd_peer_diagnostic.", peerLabels),
+
+		"controlPacketsIn":  colPromDesc(bfdSubsystem, "peer_control_packets_in_total", "Number of control packets received from the bfd peer.", peerLabels),
+		"controlPacketsOut": colPromDesc(bfdSubsystem, "peer_control_packets_out_total", "Number of control packets sent to the bfd peer.", peerLabels),
+		"echoPacketsIn":     colPromDesc(bfdSubsystem, "peer_echo_packets_in_total", "Number of echo packets received from the bfd peer.", peerLabels),
+		"echoPacketsOut":    colPromDesc(bfdSubsystem, "peer_echo_packets_out_total", "Number of echo packets sent to the bfd peer.", peerLabels),
+		"sessionUp":         colPromDesc(bfdSubsystem, "peer_session_up_total", "Number of times the bfd session went up.", peerLabels),
+		"sessionDown":       colPromDesc(bfdSubsystem, "peer_session_down_total", "Number of times the bfd session went down.", peerLabels),
 	}
 }
 
@@ -40,24 +95,42 @@ func (c *bfdCollector) Update(ch chan<- prometheus.Metric) error {
 	if err != nil {
 		return err
 	}
-	if err = processBFDPeers(ch, jsonBFDInterface, c.descriptions); err != nil {
+	peerStates := c.peerStates.begin()
+	profiles, err := processBFDPeers(ch, jsonBFDInterface, c.descriptions, peerStates)
+	if err != nil {
 		return cmdOutputProcessError(cmd, string(jsonBFDInterface), err)
 	}
+	c.peerStates.collect(ch, peerStates)
+
+	cmd = "show bfd peers counters json"
+	jsonBFDCounters, err := executeBFDCommand(cmd)
+	if err != nil {
+		return err
+	}
+	if err = processBFDPeerCounters(ch, jsonBFDCounters, profiles, c.descriptions); err != nil {
+		return cmdOutputProcessError(cmd, string(jsonBFDCounters), err)
+	}
 	return nil
 }
 
-func processBFDPeers(ch chan<- prometheus.Metric, jsonBFDInterface []byte, bfdDesc map[string]*prometheus.Desc) error {
+// processBFDPeers exports the metrics of each peer in the output of 'show bfd peers json' and
+// returns the profile of each peer, as the profile is not part of the counters output.
+func processBFDPeers(ch chan<- prometheus.Metric, jsonBFDInterface []byte, bfdDesc map[string]*prometheus.Desc, peerStates *stateObservations) (map[bfdPeerKey]string, error) {
 	var bfdPeers []bfdPeer
 	if err := json.Unmarshal(jsonBFDInterface, &bfdPeers); err != nil {
-		return err
+		return nil, err
 	}
 
+	profiles := make(map[bfdPeerKey]string, len(bfdPeers))
+
 	// metric is a count of the number of peers
 	newGauge(ch, bfdDesc["bfdPeerCount"], float64(len(bfdPeers)))
 
 	for _, p := range bfdPeers {
 
-		labels := []string{p.Local, p.Peer, p.Interface, p.Vrf}
+		key := bfdPeerKey{p.Local, p.Peer, p.Interface, p.Vrf}
+		profiles[key] = p.Profile
+		labels := bfdPeerLabelValues(key, p.Multihop, p.Profile)
 
 		// get the uptime of the connection to the peer in seconds
 		newGauge(ch, bfdDesc["bfdPeerUptime"], float64(p.Uptime), labels...)
@@ -68,16 +141,78 @@ func processBFDPeers(ch chan<- prometheus.Metric, jsonBFDInterface []byte, bfdDe
 			bfdState = 1
 		}
 		newGauge(ch, bfdDesc["bfdPeerState"], bfdState, labels...)
+		peerStates.observe(p.Status, labels...)
+
+		// intervals are reported in milliseconds
+		newGauge(ch, bfdDesc["receiveInterval"], float64(p.ReceiveInterval)/1000, labels...)
+		newGauge(ch, bfdDesc["transmitInterval"], float64(p.TransmitInterval)/1000, labels...)
+		newGauge(ch, bfdDesc["echoInterval"], float64(p.EchoInterval)/1000, labels...)
+		newGauge(ch, bfdDesc["remoteReceiveInterval"], float64(p.RemoteReceiveInterval)/1000, labels...)
+		newGauge(ch, bfdDesc["remoteTransmitInterval"], float64(p.RemoteTransmitInterval)/1000, labels...)
+		newGauge(ch, bfdDesc["remoteEchoInterval"], float64(p.RemoteEchoInterval)/1000, labels...)
+
+		newGauge(ch, bfdDesc["detectMultiplier"], float64(p.DetectMultiplier), labels...)
+		newGauge(ch, bfdDesc["remoteDetectMultiplier"], float64(p.RemoteDetectMultiplier), labels...)
+		// A system transmits at the greater of its transmit interval and the receive interval required by the other
+		// side, which declares the session down once the sender's detection multiplier of those intervals passed.
+		newGauge(ch, bfdDesc["detectionTime"], float64(p.RemoteDetectMultiplier)*float64(max(p.ReceiveInterval, p.RemoteTransmitInterval))/1000, labels...)
+		newGauge(ch, bfdDesc["remoteDetectionTime"], float64(p.DetectMultiplier)*float64(max(p.RemoteReceiveInterval, p.TransmitInterval))/1000, labels...)
+
+		if diag, ok := bfdDiagnostics[p.Diagnostic]; ok {
+			newGauge(ch, bfdDesc["diagnostic"], diag, labels...)
+		}
+		if diag, ok := bfdDiagnostics[p.RemoteDiagnostic]; ok {
+			newGauge(ch, bfdDesc["remoteDiagnostic"], diag, labels...)
+		}
+	}
+	return profiles, nil
+}
+
+func processBFDPeerCounters(ch chan<- prometheus.Metric, jsonBFDCounters []byte, profiles map[bfdPeerKey]string, bfdDesc map[string]*prometheus.Desc) error {
+	var bfdCounters []bfdPeerCounters
+	if err := json.Unmarshal(jsonBFDCounters, &bfdCounters); err != nil {
+		return err
+	}
+
+	for _, p := range bfdCounters {
+		key := bfdPeerKey{p.Local, p.Peer, p.Interface, p.Vrf}
+		labels := bfdPeerLabelValues(key, p.Multihop, profiles[key])
+
+		newCounter(ch, bfdDesc["controlPacketsIn"], float64(p.ControlPacketInput), labels...)
+		newCounter(ch, bfdDesc["controlPacketsOut"], float64(p.ControlPacketOutput), labels...)
+		newCounter(ch, bfdDesc["echoPacketsIn"], float64(p.EchoPacketInput), labels...)
+		newCounter(ch, bfdDesc["echoPacketsOut"], float64(p.EchoPacketOutput), labels...)
+		newCounter(ch, bfdDesc["sessionUp"], float64(p.SessionUp), labels...)
+		newCounter(ch, bfdDesc["sessionDown"], float64(p.SessionDown), labels...)
 	}
 	return nil
 }
 
+// bfdPeerKey identifies a bfd session across the outputs of bfdd.
+type bfdPeerKey struct {
+	local, peer, iface, vrf string
+}
+
+func bfdPeerLabelValues(key bfdPeerKey, multihop bool, profile string) []string {
+	labels := []string{key.local, key.peer, key.iface, key.vrf}
+
+	if *bfdPeerMultihop {
+		labels = append(labels, strconv.FormatBool(multihop))
+	}
+
+	if *bfdPeerProfile {
+		labels = append(labels, profile)
+	}
+	return labels
+}
+
 type bfdPeer struct {
 	Multihop               bool   `json:"multihop"`
 	Peer                   string `json:"peer"`
 	Local                  string `json:"local"`
 	Interface              string `json:"interface"`
 	Vrf                    string `json:"vrf"`
+	Profile                string `json:"profile"`
 	ID                     uint32 `json:"id"`
 	RemoteID               uint32 `json:"remote-id"`
 	Status                 string `json:"status"`
@@ -90,4 +225,20 @@ type bfdPeer struct {
 	RemoteReceiveInterval  uint32 `json:"remote-receive-interval"`
 	RemoteTransmitInterval uint32 `json:"remote-transmit-interval"`
 	RemoteEchoInterval     uint32 `json:"remote-echo-interval"`
+	DetectMultiplier       uint32 `json:"detect-multiplier"`
+	RemoteDetectMultiplier uint32 `json:"remote-detect-multiplier"`
+}
+
+type bfdPeerCounters struct {
+	Multihop            bool   `json:"multihop"`
+	Peer                string `json:"peer"`
+	Local               string `json:"local"`
+	Interface           string `json:"interface"`
+	Vrf                 string `json:"vrf"`
+	ControlPacketInput  uint64 `json:"control-packet-input"`
+	ControlPacketOutput uint64 `json:"control-packet-output"`
+	EchoPacketInput     uint64 `json:"echo-packet-input"`
+	EchoPacketOutput    uint64 `json:"echo-packet-output"`
+	SessionUp           uint64 `json:"session-up"`
+	SessionDown         uint64 `json:"session-down"`
 }
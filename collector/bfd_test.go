@@ -12,17 +12,53 @@ import (
 
 var expectedBFDMetrics = map[string]float64{
 	"frr_bfd_peer_count{}": 3,
-	"frr_bfd_peer_uptime{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}": 847716,
-	"frr_bfd_peer_state{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}":  1,
-	"frr_bfd_peer_uptime{iface=eth1,local=10.10.141.81,peer=10.10.141.62,vrf=blue}":    847595,
-	"frr_bfd_peer_state{iface=eth1,local=10.10.141.81,peer=10.10.141.62,vrf=blue}":     1,
-	"frr_bfd_peer_uptime{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":     847888,
-	"frr_bfd_peer_state{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":      0,
+	"frr_bfd_peer_uptime{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}":                           847716,
+	"frr_bfd_peer_state{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}":                            1,
+	"frr_bfd_peer_uptime{iface=eth1,local=10.10.141.81,peer=10.10.141.62,vrf=blue}":                              847595,
+	"frr_bfd_peer_state{iface=eth1,local=10.10.141.81,peer=10.10.141.62,vrf=blue}":                               1,
+	"frr_bfd_peer_uptime{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":                               847888,
+	"frr_bfd_peer_state{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":                                0,
+	"frr_bfd_peer_receive_interval_seconds{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}":         0.3,
+	"frr_bfd_peer_transmit_interval_seconds{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}":        0.3,
+	"frr_bfd_peer_echo_interval_seconds{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}":            0,
+	"frr_bfd_peer_remote_receive_interval_seconds{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}":  0.3,
+	"frr_bfd_peer_remote_transmit_interval_seconds{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}": 0.3,
+	"frr_bfd_peer_remote_echo_interval_seconds{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}":     0.3,
+	"frr_bfd_peer_diagnostic{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}":                       0,
+	"frr_bfd_peer_remote_diagnostic{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}":                0,
+	"frr_bfd_peer_receive_interval_seconds{iface=eth1,local=10.10.141.81,peer=10.10.141.62,vrf=blue}":            0.3,
+	"frr_bfd_peer_transmit_interval_seconds{iface=eth1,local=10.10.141.81,peer=10.10.141.62,vrf=blue}":           0.3,
+	"frr_bfd_peer_echo_interval_seconds{iface=eth1,local=10.10.141.81,peer=10.10.141.62,vrf=blue}":               0,
+	"frr_bfd_peer_remote_receive_interval_seconds{iface=eth1,local=10.10.141.81,peer=10.10.141.62,vrf=blue}":     0.3,
+	"frr_bfd_peer_remote_transmit_interval_seconds{iface=eth1,local=10.10.141.81,peer=10.10.141.62,vrf=blue}":    0.3,
+	"frr_bfd_peer_remote_echo_interval_seconds{iface=eth1,local=10.10.141.81,peer=10.10.141.62,vrf=blue}":        0.3,
+	"frr_bfd_peer_diagnostic{iface=eth1,local=10.10.141.81,peer=10.10.141.62,vrf=blue}":                          0,
+	"frr_bfd_peer_remote_diagnostic{iface=eth1,local=10.10.141.81,peer=10.10.141.62,vrf=blue}":                   0,
+	"frr_bfd_peer_receive_interval_seconds{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":             1,
+	"frr_bfd_peer_transmit_interval_seconds{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":            0.3,
+	"frr_bfd_peer_echo_interval_seconds{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":                0.05,
+	"frr_bfd_peer_remote_receive_interval_seconds{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":      0.5,
+	"frr_bfd_peer_remote_transmit_interval_seconds{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":     0.3,
+	"frr_bfd_peer_remote_echo_interval_seconds{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":         0.3,
+	"frr_bfd_peer_diagnostic{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":                           1,
+	"frr_bfd_peer_remote_diagnostic{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":                    3,
+	"frr_bfd_peer_detect_multiplier{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}":                3,
+	"frr_bfd_peer_remote_detect_multiplier{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}":         3,
+	"frr_bfd_peer_detection_time_seconds{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}":           0.9,
+	"frr_bfd_peer_remote_detection_time_seconds{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}":    0.9,
+	"frr_bfd_peer_detect_multiplier{iface=eth1,local=10.10.141.81,peer=10.10.141.62,vrf=blue}":                   3,
+	"frr_bfd_peer_remote_detect_multiplier{iface=eth1,local=10.10.141.81,peer=10.10.141.62,vrf=blue}":            3,
+	"frr_bfd_peer_detection_time_seconds{iface=eth1,local=10.10.141.81,peer=10.10.141.62,vrf=blue}":              0.9,
+	"frr_bfd_peer_remote_detection_time_seconds{iface=eth1,local=10.10.141.81,peer=10.10.141.62,vrf=blue}":       0.9,
+	"frr_bfd_peer_detect_multiplier{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":                    5,
+	"frr_bfd_peer_remote_detect_multiplier{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":             3,
+	"frr_bfd_peer_detection_time_seconds{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":               3,
+	"frr_bfd_peer_remote_detection_time_seconds{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":        2.5,
 }
 
 func TestProcessBFDPeers(t *testing.T) {
 	ch := make(chan prometheus.Metric, 1024)
-	if err := processBFDPeers(ch, readTestFixture(t, "show_bfd_peers.json"), getBFDDesc()); err != nil {
+	if _, err := processBFDPeers(ch, readTestFixture(t, "show_bfd_peers.json"), getBFDDesc(), nil); err != nil {
 		t.Errorf("error calling processBFDPeers ipv4unicast: %s", err)
 	}
 	close(ch)
@@ -79,3 +115,62 @@ func TestProcessBFDPeers(t *testing.T) {
 		}
 	}
 }
+
+func TestProcessBFDPeerCounters(t *testing.T) {
+	expected := map[string]float64{
+		"frr_bfd_peer_control_packets_in_total{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}":  2825720,
+		"frr_bfd_peer_control_packets_out_total{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}": 2826008,
+		"frr_bfd_peer_echo_packets_in_total{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}":     0,
+		"frr_bfd_peer_echo_packets_out_total{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}":    0,
+		"frr_bfd_peer_session_up_total{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}":          1,
+		"frr_bfd_peer_session_down_total{iface=eth0,local=10.10.141.81,peer=10.10.141.61,vrf=default}":        0,
+		"frr_bfd_peer_control_packets_in_total{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":      1502,
+		"frr_bfd_peer_control_packets_out_total{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":     4150,
+		"frr_bfd_peer_echo_packets_in_total{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":         120,
+		"frr_bfd_peer_echo_packets_out_total{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":        125,
+		"frr_bfd_peer_session_up_total{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":              3,
+		"frr_bfd_peer_session_down_total{iface=,local=10.10.141.81,peer=10.10.141.63,vrf=default}":            3,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processBFDPeerCounters(ch, readTestFixture(t, "show_bfd_peers_counters.json"), nil, getBFDDesc()); err != nil {
+		t.Errorf("error calling processBFDPeerCounters: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestProcessBFDPeerLabels(t *testing.T) {
+	*bfdPeerMultihop = true
+	*bfdPeerProfile = true
+	defer func() {
+		*bfdPeerMultihop = false
+		*bfdPeerProfile = false
+	}()
+	bfdDesc := getBFDDesc()
+
+	ch := make(chan prometheus.Metric, 1024)
+	profiles, err := processBFDPeers(ch, readTestFixture(t, "show_bfd_peers.json"), bfdDesc, nil)
+	if err != nil {
+		t.Errorf("error calling processBFDPeers: %s", err)
+	}
+	if err := processBFDPeerCounters(ch, readTestFixture(t, "show_bfd_peers_counters.json"), profiles, bfdDesc); err != nil {
+		t.Errorf("error calling processBFDPeerCounters: %s", err)
+	}
+	close(ch)
+
+	got := collectMetrics(t, ch)
+	for metric, expectedVal := range map[string]float64{
+		"frr_bfd_peer_state{iface=eth0,local=10.10.141.81,multihop=false,peer=10.10.141.61,profile=fast,vrf=default}":            1,
+		"frr_bfd_peer_detection_time_seconds{iface=,local=10.10.141.81,multihop=true,peer=10.10.141.63,profile=,vrf=default}":    3,
+		"frr_bfd_peer_session_up_total{iface=eth0,local=10.10.141.81,multihop=false,peer=10.10.141.61,profile=fast,vrf=default}": 1,
+		"frr_bfd_peer_session_down_total{iface=,local=10.10.141.81,multihop=true,peer=10.10.141.63,profile=,vrf=default}":        3,
+	} {
+		if val, ok := got[metric]; !ok {
+			t.Errorf("missing metric: %s", metric)
+		} else if val != expectedVal {
+			t.Errorf("metric %s expected value %v got %v", metric, expectedVal, val)
+		}
+	}
+}
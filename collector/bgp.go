@@ -2,14 +2,18 @@ package collector
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/netip"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"unicode"
 
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus/client_golang/prometheus"
@@ -28,6 +32,30 @@ var (
 	bgpAcceptedFilteredPrefixes = kingpin.Flag("collector.bgp.accepted-filtered-prefixes", "Enable retrieval of accepted and filtered BGP prefix counts (default: disabled).").Default("False").Bool()
 	bgpNextHopInterface         = kingpin.Flag("collector.bgp.next-hop-interface", "Adds the peer's next-hop interface label. (default: disabled).").Default("False").Bool()
 	bgpMonitoredPrefixes        = kingpin.Flag("collector.bgp.monitored-prefixes", "Path to a file listing prefixes to monitor for per-peer presence (one per line, # comments allowed).").Default("").String()
+	bgpPeerDetails              = kingpin.Flag("collector.bgp.peer-details", "Enable detailed per-peer metrics from the BGP neighbors output: messages by type, connections established and dropped, negotiated timers, queue depths and time since last read and write (default: disabled).").Default("False").Bool()
+	bgpPeerLastReset            = kingpin.Flag("collector.bgp.peer-last-reset", "Enable the frr_bgp_peer_last_reset_info and frr_bgp_peer_last_reset_seconds metrics, exporting why and when the session with a peer was last reset (default: disabled).").Default("False").Bool()
+	bgpRPKIValidation           = kingpin.Flag("collector.bgp.rpki-validation", "Enable per-peer counts of received prefixes by RPKI validation state. Requires retrieving every route received from each established peer, which is expensive for peers sending a full table. The routes of at most 4 peers are retrieved at a time (default: disabled).").Default("False").Bool()
+	bgpGracefulRestart          = kingpin.Flag("collector.bgp.graceful-restart", "Enable per-peer graceful restart metrics from the BGP neighbors graceful-restart output (default: disabled).").Default("False").Bool()
+	bgpDampening                = kingpin.Flag("collector.bgp.dampening", "Enable route flap dampening metrics: dampened and history paths per address family and peer, along with the dampening parameters (default: disabled).").Default("False").Bool()
+	bgpMaxPrefix                = kingpin.Flag("collector.bgp.max-prefix", "Enable per-peer maximum-prefix limit metrics, including the utilisation of the limit (default: disabled).").Default("False").Bool()
+	bgpPrefixLengthPeers        = kingpin.Flag("collector.bgp.prefix-length.peers", "Peer to export the distribution of received prefixes by prefix length for, as vrf/peer or as peer to match the peer in every VRF. Requires retrieving every route received from the peer. Supports multiple values (default: none).").Strings()
+	bgpPrefixLengthAdvertised   = kingpin.Flag("collector.bgp.prefix-length.advertised", "Also export the distribution of prefixes advertised to the peers of --collector.bgp.prefix-length.peers by prefix length (default: disabled).").Default("False").Bool()
+	bgpUpdateGroups             = kingpin.Flag("collector.bgp.update-groups", "Enable BGP update-group and subgroup metrics (default: disabled).").Default("False").Bool()
+	bgpAFISAFIs                 = kingpin.Flag("collector.bgp.afi-safi", "Comma-separated list of AFI/SAFI pairs collected by the bgp collector, e.g. ipv4/vpn,ipv6/labeled-unicast. An AFI without a SAFI collects all SAFIs of that AFI. Entries must not overlap each other, nor the address family of the bgp6 or bgpl2vpn collector when enabled (default: ipv4).").Default("ipv4").String()
+
+	// bgpRPKIValidationFetches bounds the number of peers whose received routes are retrieved at the same time for
+	// --collector.bgp.rpki-validation, across all scrapes.
+	bgpRPKIValidationFetches = make(chan struct{}, 4)
+
+	// bgpPrefixLengthBuckets are the upper bounds of the prefix length histograms by AFI, around the most specific
+	// prefixes commonly accepted in the DFZ, /24 and /48.
+	bgpPrefixLengthBuckets = map[string][]float64{
+		"ipv4": {8, 16, 19, 20, 21, 22, 23, 24, 32},
+		"ipv6": {16, 29, 32, 36, 40, 44, 47, 48, 64, 128},
+	}
+
+	bgpValidAFIs  = []string{"ipv4", "ipv6", "l2vpn"}
+	bgpValidSAFIs = []string{"unicast", "multicast", "vpn", "labeled-unicast", "flowspec", "evpn"}
 )
 
 func init() {
@@ -39,8 +67,55 @@ func init() {
 type bgpCollector struct {
 	logger            *slog.Logger
 	descriptions      map[string]*prometheus.Desc
-	afi               string
+	afiSafis          []bgpAFISAFI
 	monitoredPrefixes []string
+	peerStates        *stateTracker
+}
+
+// bgpAFISAFI is an address family to collect. An empty SAFI collects all SAFIs of the AFI.
+type bgpAFISAFI struct {
+	afi, safi string
+}
+
+// overlaps returns whether a and b collect any SAFI in common, which would export duplicate series.
+func (a bgpAFISAFI) overlaps(b bgpAFISAFI) bool {
+	return a.afi == b.afi && (a.safi == "" || b.safi == "" || a.safi == b.safi)
+}
+
+func (a bgpAFISAFI) String() string {
+	if a.safi == "" {
+		return a.afi
+	}
+	return a.afi + "/" + a.safi
+}
+
+// parseBGPAFISAFIs parses a comma-separated list of AFI/SAFI pairs, such as "ipv4,ipv6/vpn".
+func parseBGPAFISAFIs(list string) ([]bgpAFISAFI, error) {
+	var afiSafis []bgpAFISAFI
+	for _, pair := range strings.Split(list, ",") {
+		pair = strings.ToLower(strings.TrimSpace(pair))
+		if pair == "" {
+			continue
+		}
+		afi, safi, _ := strings.Cut(pair, "/")
+		if !slices.Contains(bgpValidAFIs, afi) {
+			return nil, fmt.Errorf("invalid AFI %q in %q, must be one of %s", afi, pair, strings.Join(bgpValidAFIs, ", "))
+		}
+		if safi != "" && !slices.Contains(bgpValidSAFIs, safi) {
+			return nil, fmt.Errorf("invalid SAFI %q in %q, must be one of %s", safi, pair, strings.Join(bgpValidSAFIs, ", "))
+		}
+		afiSafi := bgpAFISAFI{afi: afi, safi: safi}
+		for _, other := range afiSafis {
+			if afiSafi.overlaps(other) {
+				return nil, fmt.Errorf("%q overlaps %q", afiSafi, other)
+			}
+		}
+		afiSafis = append(afiSafis, afiSafi)
+	}
+	if len(afiSafis) == 0 {
+		return nil, fmt.Errorf("no AFI/SAFI pairs in %q", list)
+	}
+	return afiSafis, nil
 }
 
 // NewBGPCollector collects BGP metrics, implemented as per the Collector interface.
@@ -53,13 +128,30 @@ func NewBGPCollector(logger *slog.Logger) (Collector, error) {
 			return nil, err
 		}
 	}
-	return &bgpCollector{logger: logger, descriptions: getBGPDesc(), afi: "ipv4", monitoredPrefixes: prefixes}, nil
+	afiSafis, err := parseBGPAFISAFIs(*bgpAFISAFIs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse --collector.bgp.afi-safi: %w", err)
+	}
+	// The bgp6 and bgpl2vpn collectors export the same series as the bgp collector for their address family.
+	for name, collected := range map[string]bgpAFISAFI{
+		bgpSubsystem + "6":     {afi: "ipv6"},
+		bgpSubsystem + "l2vpn": {afi: "l2vpn", safi: "evpn"},
+	} {
+		if enabled, ok := collectorState[name]; !ok || !*enabled {
+			continue
+		}
+		for _, afiSafi := range afiSafis {
+			if afiSafi.overlaps(collected) {
+				return nil, fmt.Errorf("--collector.bgp.afi-safi entry %q overlaps the enabled %s collector", afiSafi, name)
+			}
+		}
+	}
+	return &bgpCollector{logger: logger, descriptions: getBGPDesc(), afiSafis: afiSafis, monitoredPrefixes: prefixes, peerStates: newStateTracker(bgpSubsystem, bgpSubsystem, "peer", getBGPPeerLabels())}, nil
 }
 
-func getBGPDesc() map[string]*prometheus.Desc {
-	bgpLabels := []string{"vrf", "afi", "safi", "local_as"}
-	bgpPeerTypeLabels := []string{"type", "afi", "safi"}
-	bgpPeerLabels := append(bgpLabels, "peer", "peer_as")
+// getBGPPeerLabels returns the labels of per-peer metrics, which depend on the optional peer labels enabled.
+func getBGPPeerLabels() []string {
+	bgpPeerLabels := []string{"vrf", "afi", "safi", "local_as", "peer", "peer_as"}
 
 	if *bgpPeerDescs {
 		bgpPeerLabels = append(bgpPeerLabels, "peer_desc")
@@ -76,8 +168,22 @@ func getBGPDesc() map[string]*prometheus.Desc {
 	if *bgpNextHopInterface {
 		bgpPeerLabels = append(bgpPeerLabels, "nexthop_interface")
 	}
+	return bgpPeerLabels
+}
+
+func getBGPDesc() map[string]*prometheus.Desc {
+	bgpLabels := []string{"vrf", "afi", "safi", "local_as"}
+	bgpPeerTypeLabels := []string{"type", "afi", "safi"}
+	bgpPeerLabels := getBGPPeerLabels()
 
 	bgpPeerPrefixLabels := append(append([]string{}, bgpPeerLabels...), "prefix")
+	bgpPeerMsgTypeLabels := append(append([]string{}, bgpPeerLabels...), "message_type")
+	bgpPeerRPKILabels := append(append([]string{}, bgpPeerLabels...), "state")
+	bgpPeerGRLabels := append(append([]string{}, bgpPeerLabels...), "local_mode", "remote_mode")
+	bgpSubgroupLabels := append(append([]string{}, bgpLabels...), "update_group", "subgroup")
+	bgpDampeningLabels := append(append([]string{}, bgpLabels...), "state")
+	bgpPeerDampeningLabels := append(append([]string{}, bgpPeerLabels...), "state")
+	bgpPeerLastResetLabels := append(append([]string{}, bgpPeerLabels...), "reason", "notification_code", "notification_subcode")
 
 	return map[string]*prometheus.Desc{
 		"ribCount":              colPromDesc(bgpSubsystem, "rib_count_total", "Number of routes in the RIB.", bgpLabels),
@@ -97,12 +203,62 @@ func getBGPDesc() map[string]*prometheus.Desc {
 		"peerTypesUp":           colPromDesc(bgpSubsystem, "peer_types_up", "Total Number of Peer Types that are Up.", bgpPeerTypeLabels),
 		"prefixReceived":        colPromDesc(bgpSubsystem, "peer_prefix_received", "Whether a monitored prefix is received from the peer (1 = present, 0 = absent).", bgpPeerPrefixLabels),
 		"prefixAdvertised":      colPromDesc(bgpSubsystem, "peer_prefix_advertised", "Whether a monitored prefix is advertised to the peer (1 = present, 0 = absent).", bgpPeerPrefixLabels),
+		"msgTypeRcvd":           colPromDesc(bgpSubsystem, "peer_message_type_received_total", "Number of received messages by message type.", bgpPeerMsgTypeLabels),
+		"msgTypeSent":           colPromDesc(bgpSubsystem, "peer_message_type_sent_total", "Number of sent messages by message type.", bgpPeerMsgTypeLabels),
+		"connEstablished":       colPromDesc(bgpSubsystem, "peer_connections_established_total", "Number of times the session with the peer was established.", bgpPeerLabels),
+		"connDropped":           colPromDesc(bgpSubsystem, "peer_connections_dropped_total", "Number of times the session with the peer was dropped.", bgpPeerLabels),
+		"holdTime":              colPromDesc(bgpSubsystem, "peer_hold_time_seconds", "Negotiated hold time of the session with the peer.", bgpPeerLabels),
+		"keepaliveInterval":     colPromDesc(bgpSubsystem, "peer_keepalive_interval_seconds", "Negotiated keepalive interval of the session with the peer.", bgpPeerLabels),
+		"inQueue":               colPromDesc(bgpSubsystem, "peer_input_queue_depth", "Number of messages in the input queue of the peer.", bgpPeerLabels),
+		"outQueue":              colPromDesc(bgpSubsystem, "peer_output_queue_depth", "Number of messages in the output queue of the peer.", bgpPeerLabels),
+		"lastRead":              colPromDesc(bgpSubsystem, "peer_last_read_seconds", "Time since a message was last read from the peer.", bgpPeerLabels),
+		"lastWrite":             colPromDesc(bgpSubsystem, "peer_last_write_seconds", "Time since a message was last written to the peer.", bgpPeerLabels),
+		"lastResetInfo":         colPromDesc(bgpSubsystem, "peer_last_reset_info", "Reason the session with the peer was last reset, along with the error code and subcode of the notification sent or received. Notification labels are empty when the reset was not caused by a notification.", bgpPeerLastResetLabels),
+		"lastReset":             colPromDesc(bgpSubsystem, "peer_last_reset_seconds", "Time since the session with the peer was last reset.", bgpPeerLabels),
+		"rpkiPrefixes":          colPromDesc(bgpSubsystem, "peer_rpki_prefixes", "Number of prefixes received from the peer by RPKI validation state (valid, invalid or notfound).", bgpPeerRPKILabels),
+		"grInfo":                colPromDesc(bgpSubsystem, "peer_graceful_restart_info", "Local and remote graceful restart mode (helper, restarter or disabled) of the peer.", bgpPeerGRLabels),
+		"grNegotiated":          colPromDesc(bgpSubsystem, "peer_graceful_restart_negotiated", "Whether graceful restart was negotiated with the peer, inferred from both the local and remote graceful restart modes being helper or restarter (1 = negotiated, 0 = not negotiated).", bgpPeerLabels),
+		"grRestartTimer":        colPromDesc(bgpSubsystem, "peer_graceful_restart_restart_timer_seconds", "Restart time received from the peer in its graceful restart capability.", bgpPeerLabels),
+		"grStalePathTimer":      colPromDesc(bgpSubsystem, "peer_graceful_restart_stale_path_timer_seconds", "Time stale paths of the peer are retained after it restarts.", bgpPeerLabels),
+		"grHelperActive":        colPromDesc(bgpSubsystem, "peer_graceful_restart_helper_active", "Whether the peer is restarting and its stale paths are being retained (1 = active, 0 = inactive).", bgpPeerLabels),
+		"dampPaths":             colPromDesc(bgpSubsystem, "dampening_paths", "Number of paths with route flap dampening information by state (damped or history).", bgpDampeningLabels),
+		"peerDampPaths":         colPromDesc(bgpSubsystem, "peer_dampening_paths", "Number of paths received from the peer with route flap dampening information by state (damped or history).", bgpPeerDampeningLabels),
+		"dampHalfLife":          colPromDesc(bgpSubsystem, "dampening_half_life_seconds", "Route flap dampening half-life.", bgpLabels),
+		"dampReuse":             colPromDesc(bgpSubsystem, "dampening_reuse_penalty", "Penalty below which a dampened route is reused.", bgpLabels),
+		"dampSuppress":          colPromDesc(bgpSubsystem, "dampening_suppress_penalty", "Penalty above which a route is suppressed.", bgpLabels),
+		"dampMaxSuppressTime":   colPromDesc(bgpSubsystem, "dampening_max_suppress_time_seconds", "Maximum time a route can be suppressed.", bgpLabels),
+		"dampMaxPenalty":        colPromDesc(bgpSubsystem, "dampening_max_suppress_penalty", "Maximum penalty of a route.", bgpLabels),
+		"maxPrefix":             colPromDesc(bgpSubsystem, "peer_max_prefix_limit", "Configured maximum number of prefixes accepted from the peer.", bgpPeerLabels),
+		"maxPrefixWarningOnly":  colPromDesc(bgpSubsystem, "peer_max_prefix_warning_only", "Whether exceeding the maximum-prefix limit only logs a warning instead of tearing down the session (1 = warning only, 0 = teardown).", bgpPeerLabels),
+		"maxPrefixRestart":      colPromDesc(bgpSubsystem, "peer_max_prefix_restart_interval_seconds", "Time after which a session torn down for exceeding the maximum-prefix limit is restarted. 0 when the session is not restarted automatically.", bgpPeerLabels),
+		"maxPrefixUtilisation":  colPromDesc(bgpSubsystem, "peer_max_prefix_utilisation_ratio", "Ratio of accepted prefixes to the maximum-prefix limit of the peer.", bgpPeerLabels),
+		"prefixLengthRcvd":      colPromDesc(bgpSubsystem, "peer_received_prefix_length", "Distribution of the prefix lengths of the prefixes received from the peer.", bgpPeerLabels),
+		"prefixLengthAdv":       colPromDesc(bgpSubsystem, "peer_advertised_prefix_length", "Distribution of the prefix lengths of the prefixes advertised to the peer.", bgpPeerLabels),
+		"updateGroups":          colPromDesc(bgpSubsystem, "update_groups", "Number of update groups.", bgpLabels),
+		"updateSubgroups":       colPromDesc(bgpSubsystem, "update_subgroups", "Number of update subgroups.", bgpLabels),
+		"subgroupPeers":         colPromDesc(bgpSubsystem, "update_subgroup_peers", "Number of peers in the update subgroup.", bgpSubgroupLabels),
+		"subgroupSplits":        colPromDesc(bgpSubsystem, "update_subgroup_splits_total", "Number of times the update subgroup was split.", bgpSubgroupLabels),
+		"subgroupMerges":        colPromDesc(bgpSubsystem, "update_subgroup_merges_total", "Number of times the update subgroup was merged.", bgpSubgroupLabels),
+		"subgroupQueue":         colPromDesc(bgpSubsystem, "update_subgroup_packet_queue_length", "Number of packets in the update subgroup's output queue.", bgpSubgroupLabels),
+		"subgroupAdjOut":        colPromDesc(bgpSubsystem, "update_subgroup_adj_out_entries", "Number of entries in the update subgroup's adj-out.", bgpSubgroupLabels),
+		"rpkiInvalidBest":       colPromDesc(bgpSubsystem, "peer_rpki_invalid_best_prefixes", "Number of RPKI invalid prefixes received from the peer that are selected as best path.", bgpPeerLabels),
 	}
 }
 
 // Update implemented as per the Collector interface.
 func (c *bgpCollector) Update(ch chan<- prometheus.Metric) error {
-	return collectBGP(ch, c.afi, c.logger, c.descriptions, c.monitoredPrefixes)
+	neighbors, err := getBGPNeighbors()
+	if err != nil {
+		return err
+	}
+	peerStates := c.peerStates.begin()
+	for _, afiSafi := range c.afiSafis {
+		if err := collectBGP(ch, afiSafi.afi, afiSafi.safi, c.logger, c.descriptions, c.monitoredPrefixes, neighbors, peerStates); err != nil {
+			return err
+		}
+	}
+	c.peerStates.collect(ch, peerStates)
+	return nil
 }
 
 // NewBGP6Collector collects BGPv6 metrics, implemented as per the Collector interface.
@@ -115,17 +271,18 @@ func NewBGP6Collector(logger *slog.Logger) (Collector, error) {
 			return nil, err
 		}
 	}
-	return &bgpCollector{logger: logger, descriptions: getBGPDesc(), afi: "ipv6", monitoredPrefixes: prefixes}, nil
+	return &bgpCollector{logger: logger, descriptions: getBGPDesc(), afiSafis: []bgpAFISAFI{{afi: "ipv6"}}, monitoredPrefixes: prefixes, peerStates: newStateTracker(bgpSubsystem+"6", bgpSubsystem, "peer", getBGPPeerLabels())}, nil
 }
 
 type bgpL2VPNCollector struct {
 	logger       *slog.Logger
 	descriptions map[string]*prometheus.Desc
+	peerStates   *stateTracker
 }
 
 // NewBGPL2VPNCollector collects BGP L2VPN metrics, implemented as per the Collector interface.
 func NewBGPL2VPNCollector(logger *slog.Logger) (Collector, error) {
-	return &bgpL2VPNCollector{logger: logger, descriptions: getBGPL2VPNDesc()}, nil
+	return &bgpL2VPNCollector{logger: logger, descriptions: getBGPL2VPNDesc(), peerStates: newStateTracker(bgpSubsystem+"l2vpn", bgpSubsystem, "peer", getBGPPeerLabels())}, nil
 }
 
 func getBGPL2VPNDesc() map[string]*prometheus.Desc {
@@ -142,9 +299,15 @@ func getBGPL2VPNDesc() map[string]*prometheus.Desc {
 
 // Update implemented as per the Collector interface.
 func (c *bgpL2VPNCollector) Update(ch chan<- prometheus.Metric) error {
-	if err := collectBGP(ch, "l2vpn", c.logger, c.descriptions, nil); err != nil {
+	neighbors, err := getBGPNeighbors()
+	if err != nil {
+		return err
+	}
+	peerStates := c.peerStates.begin()
+	if err := collectBGP(ch, "l2vpn", "evpn", c.logger, c.descriptions, nil, neighbors, peerStates); err != nil {
 		return err
 	}
+	c.peerStates.collect(ch, peerStates)
 	cmd := "show evpn vni json"
 	jsonBGPL2vpnEvpnSum, err := executeZebraCommand(cmd)
 	if err != nil {
@@ -189,64 +352,67 @@ func processBgpL2vpnEvpnSummary(ch chan<- prometheus.Metric, jsonBGPL2vpnEvpnSum
 	return nil
 }
 
-func collectBGP(ch chan<- prometheus.Metric, AFI string, logger *slog.Logger, desc map[string]*prometheus.Desc, monitoredPrefixes []string) error {
-	SAFI := ""
-
-	switch AFI {
-	case "ipv4", "ipv6":
-		SAFI = ""
-	case "l2vpn":
-		SAFI = "evpn"
-	}
+func collectBGP(ch chan<- prometheus.Metric, AFI string, SAFI string, logger *slog.Logger, desc map[string]*prometheus.Desc, monitoredPrefixes []string, neighbors bgpNeighbors, peerStates *stateObservations) error {
 	cmd := fmt.Sprintf("show bgp vrf all %s %s summary json", AFI, SAFI)
 	jsonBGPSum, err := executeBGPCommand(cmd)
 	if err != nil {
 		return err
 	}
-	if err := processBGPSummary(ch, jsonBGPSum, AFI, SAFI, logger, desc, monitoredPrefixes); err != nil {
+	if err := processBGPSummary(ch, jsonBGPSum, AFI, SAFI, logger, desc, monitoredPrefixes, neighbors, peerStates); err != nil {
 		return cmdOutputProcessError(cmd, string(jsonBGPSum), err)
 	}
 	return nil
 }
 
-func processBGPSummary(ch chan<- prometheus.Metric, jsonBGPSum []byte, AFI string, SAFI string, logger *slog.Logger, bgpDesc map[string]*prometheus.Desc, monitoredPrefixes []string) error {
+// bgpSAFIFromKey converts an address family key of the BGP summary JSON, such as ipv4Unicast,
+// ipv6LabeledUnicast or l2VpnEvpn, to the SAFI as used by vtysh, such as unicast,
+// labeled-unicast or evpn.
+func bgpSAFIFromKey(AFI string, key string) string {
+	if len(key) > len(AFI) && strings.EqualFold(key[:len(AFI)], AFI) {
+		key = key[len(AFI):]
+	}
+
+	var safi strings.Builder
+	for i, r := range key {
+		if unicode.IsUpper(r) && i > 0 {
+			safi.WriteByte('-')
+		}
+		safi.WriteRune(unicode.ToLower(r))
+	}
+	return safi.String()
+}
+
+func processBGPSummary(ch chan<- prometheus.Metric, jsonBGPSum []byte, AFI string, SAFI string, logger *slog.Logger, bgpDesc map[string]*prometheus.Desc, monitoredPrefixes []string, neighbors bgpNeighbors, peerStates *stateObservations) error {
+	// jsonMap is keyed by VRF and then SAFI.
 	var jsonMap map[string]map[string]bgpProcess
 
-	// if we've specified SAFI in the command, we won't have the SAFI layer of array to loop through
-	// so we simulate it here, rather than using a conditional and writing almost the same code twice
-	if AFI == "l2vpn" && SAFI == "evpn" {
-		// since we need to massage the format a bit, unmarshall into a temp variable
+	if SAFI != "" {
+		// when the SAFI is specified in the command, the output has no SAFI layer, so add it here
+		// rather than writing almost the same code twice
 		var tempJSONMap map[string]bgpProcess
 		if err := json.Unmarshal(jsonBGPSum, &tempJSONMap); err != nil {
 			return err
 		}
 		jsonMap = map[string]map[string]bgpProcess{}
 		for vrfName, vrfData := range tempJSONMap {
-			jsonMap[vrfName] = map[string]bgpProcess{"xxxxevpn": vrfData}
+			jsonMap[vrfName] = map[string]bgpProcess{SAFI: vrfData}
 		}
 	} else {
-		// we have the format we expect, unmarshall directly into jsonMap
-		if err := json.Unmarshal(jsonBGPSum, &jsonMap); err != nil {
+		// without a SAFI, the output is keyed by address family, e.g. ipv4Unicast
+		var afJSONMap map[string]map[string]bgpProcess
+		if err := json.Unmarshal(jsonBGPSum, &afJSONMap); err != nil {
 			return err
 		}
-	}
-
-	var peerDesc map[string]bgpVRF
-	var err error
-	if *bgpPeerTypes || *bgpPeerDescs || *bgpPeerGroups || *bgpAcceptedFilteredPrefixes {
-		peerDesc, err = getBGPPeerDesc()
-		if err != nil {
-			return err
+		jsonMap = map[string]map[string]bgpProcess{}
+		for vrfName, vrfData := range afJSONMap {
+			jsonMap[vrfName] = map[string]bgpProcess{}
+			for afKey, afData := range vrfData {
+				jsonMap[vrfName][bgpSAFIFromKey(AFI, afKey)] = afData
+			}
 		}
 	}
 
-	var bgpNextHop map[string]bgpNextHop
-	if *bgpNextHopInterface {
-		bgpNextHop, err = getBGPNexthop()
-		if err != nil {
-			return err
-		}
-	}
+	peerDesc, bgpNextHop, peerGR := neighbors.desc, neighbors.nextHop, neighbors.gracefulRestart
 
 	peerTypes := make(map[string]map[string]float64)
 	wg := &sync.WaitGroup{}
@@ -254,7 +420,7 @@ func processBGPSummary(ch chan<- prometheus.Metric, jsonBGPSum []byte, AFI strin
 		for safiName, safiData := range vrfData {
 			// The labels are "vrf", "afi",  "safi", "local_as"
 			localAs := strconv.FormatUint(uint64(safiData.AS), 10)
-			procLabels := []string{strings.ToLower(vrfName), strings.ToLower(AFI), strings.ToLower(safiName[4:]), localAs}
+			procLabels := []string{strings.ToLower(vrfName), strings.ToLower(AFI), safiName, localAs}
 			// No point collecting metrics if no peers configured.
 			if safiData.PeerCount != 0 {
 				newGauge(ch, bgpDesc["ribCount"], float64(safiData.RIBCount), procLabels...)
@@ -264,9 +430,10 @@ func processBGPSummary(ch chan<- prometheus.Metric, jsonBGPSum []byte, AFI strin
 				newGauge(ch, bgpDesc["peerGroupCount"], float64(safiData.PeerGroupCount), procLabels...)
 				newGauge(ch, bgpDesc["peerGroupMemory"], float64(safiData.PeerGroupMemory), procLabels...)
 
+				dampPeerLabels := make(map[string][]string)
 				for peerIP, peerData := range safiData.Peers {
 					// The labels are "vrf", "afi", "safi", "local_as", "peer", "remote_as"
-					peerLabels := []string{strings.ToLower(vrfName), strings.ToLower(AFI), strings.ToLower(safiName[4:]), localAs, peerIP, strconv.FormatUint(uint64(peerData.RemoteAs), 10)}
+					peerLabels := []string{strings.ToLower(vrfName), strings.ToLower(AFI), safiName, localAs, peerIP, strconv.FormatUint(uint64(peerData.RemoteAs), 10)}
 
 					if *bgpPeerDescs {
 						d := peerDesc[vrfName].BGPNeighbors[peerIP].Desc
@@ -316,7 +483,7 @@ func processBGPSummary(ch chan<- prometheus.Metric, jsonBGPSum []byte, AFI strin
 						newGauge(ch, bgpDesc["prefixAdvertisedCount"], float64(*peerData.PfxSnt), peerLabels...)
 					} else if *bgpAdvertisedPrefixes {
 						wg.Add(1)
-						go getPeerAdvertisedPrefixes(ch, wg, AFI, safiName[4:], vrfName, peerIP, logger, bgpDesc, peerLabels...)
+						go getPeerAdvertisedPrefixes(ch, wg, AFI, safiName, vrfName, peerIP, logger, bgpDesc, peerLabels...)
 					}
 
 					newCounter(ch, bgpDesc["msgRcvd"], float64(peerData.MsgRcvd), peerLabels...)
@@ -333,10 +500,34 @@ func processBGPSummary(ch chan<- prometheus.Metric, jsonBGPSum []byte, AFI strin
 					newGauge(ch, bgpDesc["prefixReceivedCount"], prefixReceived, peerLabels...)
 
 					if *bgpAcceptedFilteredPrefixes {
-						afiSafi := strings.ToLower(AFI) + safiName[4:]
+						afiSafi := strings.ToLower(AFI) + strings.ReplaceAll(safiName, "-", "")
 						processPeerAcceptedFilteredPrefixes(ch, afiSafi, peerDesc[vrfName].BGPNeighbors[peerIP].AddressFamilyInfo, prefixReceived, bgpDesc, peerLabels)
 					}
 
+					if *bgpMaxPrefix {
+						afiSafi := strings.ToLower(AFI) + strings.ReplaceAll(safiName, "-", "")
+						processPeerMaxPrefix(ch, afiSafi, peerDesc[vrfName].BGPNeighbors[peerIP].AddressFamilyInfo, bgpDesc, peerLabels)
+					}
+
+					if *bgpPeerDetails {
+						if neighbor, ok := peerDesc[vrfName].BGPNeighbors[peerIP]; ok {
+							processPeerDetails(ch, neighbor, bgpDesc, peerLabels)
+						}
+					}
+
+					if *bgpGracefulRestart {
+						if peer, ok := peerGR[vrfName].Peers[peerIP]; ok {
+							afiSafi := strings.ToLower(AFI) + strings.ReplaceAll(safiName, "-", "")
+							processPeerGracefulRestart(ch, afiSafi, peer, bgpDesc, peerLabels)
+						}
+					}
+
+					if *bgpPeerLastReset {
+						if neighbor, ok := peerDesc[vrfName].BGPNeighbors[peerIP]; ok {
+							processPeerLastReset(ch, neighbor, bgpDesc, peerLabels)
+						}
+					}
+
 					var peerDescTypes map[string]string
 					if *bgpPeerTypes {
 						if err := json.Unmarshal([]byte(peerDesc[vrfName].BGPNeighbors[peerIP].Desc), &peerDescTypes); err != nil {
@@ -345,14 +536,14 @@ func processBGPSummary(ch chan<- prometheus.Metric, jsonBGPSum []byte, AFI strin
 						}
 
 						// add key for this SAFI if it doesn't exist
-						if _, exist := peerTypes[strings.ToLower(safiName[4:])]; !exist {
-							peerTypes[strings.ToLower(safiName[4:])] = make(map[string]float64)
+						if _, exist := peerTypes[safiName]; !exist {
+							peerTypes[safiName] = make(map[string]float64)
 						}
 
 						for _, descKey := range *frrBGPDescKey {
 							if peerDescTypes[descKey] != "" {
-								if _, exist := peerTypes[strings.ToLower(safiName[4:])][strings.TrimSpace(peerDescTypes[descKey])]; !exist {
-									peerTypes[strings.ToLower(safiName[4:])][strings.TrimSpace(peerDescTypes[descKey])] = 0
+								if _, exist := peerTypes[safiName][strings.TrimSpace(peerDescTypes[descKey])]; !exist {
+									peerTypes[safiName][strings.TrimSpace(peerDescTypes[descKey])] = 0
 								}
 							}
 						}
@@ -364,19 +555,40 @@ func processBGPSummary(ch chan<- prometheus.Metric, jsonBGPSum []byte, AFI strin
 						if *bgpPeerTypes {
 							for _, descKey := range *frrBGPDescKey {
 								if peerDescTypes[descKey] != "" {
-									peerTypes[strings.ToLower(safiName[4:])][strings.TrimSpace(peerDescTypes[descKey])]++
+									peerTypes[safiName][strings.TrimSpace(peerDescTypes[descKey])]++
 								}
 							}
 						}
 						if len(monitoredPrefixes) > 0 {
 							wg.Add(1)
-							go getPeerPrefixPresence(ch, wg, AFI, safiName[4:], vrfName, peerIP, monitoredPrefixes, logger, bgpDesc, peerLabels...)
+							go getPeerPrefixPresence(ch, wg, AFI, safiName, vrfName, peerIP, monitoredPrefixes, logger, bgpDesc, peerLabels...)
+						}
+						if bgpPrefixLengthPeerSelected(*bgpPrefixLengthPeers, vrfName, peerIP) {
+							wg.Add(1)
+							go getPeerPrefixLengths(ch, wg, AFI, safiName, vrfName, peerIP, logger, bgpDesc, peerLabels...)
+						}
+						if *bgpRPKIValidation {
+							wg.Add(1)
+							bgpRPKIValidationFetches <- struct{}{}
+							go getPeerRPKIValidation(ch, wg, AFI, safiName, vrfName, peerIP, logger, bgpDesc, peerLabels...)
 						}
 					case "idle (admin)":
 						peerState = 2
 					}
 					newGauge(ch, bgpDesc["state"], peerState, peerLabels...)
+					peerStates.observe(strings.ToLower(peerData.State), peerLabels...)
+
+					dampPeerLabels[peerIP] = peerLabels
+				}
+
+				if *bgpUpdateGroups {
+					wg.Add(1)
+					go getBGPUpdateGroups(ch, wg, AFI, safiName, vrfName, procLabels, logger, bgpDesc)
+				}
 
+				if *bgpDampening {
+					wg.Add(1)
+					go getBGPDampening(ch, wg, AFI, safiName, vrfName, procLabels, dampPeerLabels, logger, bgpDesc)
 				}
 			}
 		}
@@ -423,9 +635,8 @@ type bgpRoutes struct {
 	Routes map[string][]json.RawMessage `json:"routes"`
 }
 
-// processPeerAcceptedFilteredPrefixes writes the accepted and filtered prefix
-// counts for a peer using the acceptedPrefixCounter
-func processPeerAcceptedFilteredPrefixes(ch chan<- prometheus.Metric, afiSafi string, afInfo map[string]bgpNeighborAFISAFI, prefixesReceived float64, bgpDesc map[string]*prometheus.Desc, peerLabels []string) {
+// lookupAFISAFI returns the value of the afiSafi key of a per address family map of bgpd's JSON output.
+func lookupAFISAFI[T any](afInfo map[string]T, afiSafi string) (T, bool) {
 	info, ok := afInfo[afiSafi]
 	if !ok {
 		// normalize the afi/safi key to handle older FRR versions that use
@@ -434,11 +645,17 @@ func processPeerAcceptedFilteredPrefixes(ch chan<- prometheus.Metric, afiSafi st
 		want := strings.ToLower(strings.ReplaceAll(afiSafi, " ", ""))
 		for k, v := range afInfo {
 			if strings.ToLower(strings.ReplaceAll(k, " ", "")) == want {
-				info, ok = v, true
-				break
+				return v, true
 			}
 		}
 	}
+	return info, ok
+}
+
+// processPeerAcceptedFilteredPrefixes writes the accepted and filtered prefix
+// counts for a peer using the acceptedPrefixCounter
+func processPeerAcceptedFilteredPrefixes(ch chan<- prometheus.Metric, afiSafi string, afInfo map[string]bgpNeighborAFISAFI, prefixesReceived float64, bgpDesc map[string]*prometheus.Desc, peerLabels []string) {
+	info, ok := lookupAFISAFI(afInfo, afiSafi)
 	if !ok {
 		return
 	}
@@ -448,6 +665,106 @@ func processPeerAcceptedFilteredPrefixes(ch chan<- prometheus.Metric, afiSafi st
 	newGauge(ch, bgpDesc["prefixFilteredCount"], prefixesReceived-prefixesAccepted, peerLabels...)
 }
 
+// processPeerMaxPrefix writes the maximum-prefix limit of a peer, when configured. bgpd applies the limit to the
+// accepted prefixes, so the utilisation is computed from the acceptedPrefixCounter.
+func processPeerMaxPrefix(ch chan<- prometheus.Metric, afiSafi string, afInfo map[string]bgpNeighborAFISAFI, bgpDesc map[string]*prometheus.Desc, peerLabels []string) {
+	info, ok := lookupAFISAFI(afInfo, afiSafi)
+	if !ok || info.PrefixAllowedMax == nil || *info.PrefixAllowedMax == 0 {
+		return
+	}
+
+	limit := float64(*info.PrefixAllowedMax)
+	newGauge(ch, bgpDesc["maxPrefix"], limit, peerLabels...)
+	newGauge(ch, bgpDesc["maxPrefixWarningOnly"], boolToFloat(info.PrefixAllowedMaxWarning), peerLabels...)
+	newGauge(ch, bgpDesc["maxPrefixRestart"], float64(info.PrefixAllowedRestartIntervalMsecs)*0.001, peerLabels...)
+	newGauge(ch, bgpDesc["maxPrefixUtilisation"], float64(info.AcceptedPrefixCounter)/limit, peerLabels...)
+}
+
+// processPeerDetails writes the detailed session metrics of a peer from the BGP neighbors output.
+func processPeerDetails(ch chan<- prometheus.Metric, neighbor bgpNeighbor, bgpDesc map[string]*prometheus.Desc, peerLabels []string) {
+	if stats := neighbor.MessageStats; stats != nil {
+		for msgType, counts := range map[string][2]uint64{
+			"open":          {stats.OpensRecv, stats.OpensSent},
+			"update":        {stats.UpdatesRecv, stats.UpdatesSent},
+			"keepalive":     {stats.KeepalivesRecv, stats.KeepalivesSent},
+			"notification":  {stats.NotificationsRecv, stats.NotificationsSent},
+			"route_refresh": {stats.RouteRefreshRecv, stats.RouteRefreshSent},
+		} {
+			msgTypeLabels := append(append([]string{}, peerLabels...), msgType)
+			newCounter(ch, bgpDesc["msgTypeRcvd"], float64(counts[0]), msgTypeLabels...)
+			newCounter(ch, bgpDesc["msgTypeSent"], float64(counts[1]), msgTypeLabels...)
+		}
+		newGauge(ch, bgpDesc["inQueue"], float64(stats.DepthInq), peerLabels...)
+		newGauge(ch, bgpDesc["outQueue"], float64(stats.DepthOutq), peerLabels...)
+	}
+
+	newCounter(ch, bgpDesc["connEstablished"], float64(neighbor.ConnectionsEstablished), peerLabels...)
+	newCounter(ch, bgpDesc["connDropped"], float64(neighbor.ConnectionsDropped), peerLabels...)
+	newGauge(ch, bgpDesc["holdTime"], float64(neighbor.HoldTimeMsecs)*0.001, peerLabels...)
+	newGauge(ch, bgpDesc["keepaliveInterval"], float64(neighbor.KeepAliveIntervalMsecs)*0.001, peerLabels...)
+
+	// The time since the last read and write is only present once the session has been established.
+	if neighbor.LastReadMsecs != nil {
+		newGauge(ch, bgpDesc["lastRead"], float64(*neighbor.LastReadMsecs)*0.001, peerLabels...)
+	}
+	if neighbor.LastWriteMsecs != nil {
+		newGauge(ch, bgpDesc["lastWrite"], float64(*neighbor.LastWriteMsecs)*0.001, peerLabels...)
+	}
+}
+
+// processPeerLastReset writes the reason and time of the last reset of a peer from the BGP neighbors output.
+// The reason and notification error code and subcode are the fixed strings bgpd uses in 'show bgp neighbors',
+// such as "Notification received" and "Hold Timer Expired/Unspecific", which keeps the label values bounded.
+func processPeerLastReset(ch chan<- prometheus.Metric, neighbor bgpNeighbor, bgpDesc map[string]*prometheus.Desc, peerLabels []string) {
+	if neighbor.LastResetMsecs != nil {
+		newGauge(ch, bgpDesc["lastReset"], float64(*neighbor.LastResetMsecs)*0.001, peerLabels...)
+	}
+
+	// Older versions of FRR use the lastResetDueTo JSON element for the reset reason, later versions use lastReset.
+	reason := neighbor.LastReset
+	if reason == "" {
+		reason = neighbor.LastResetDueTo
+	}
+	if reason == "" {
+		return
+	}
+
+	code, subcode, _ := strings.Cut(neighbor.LastNotificationReason, "/")
+	newGauge(ch, bgpDesc["lastResetInfo"], 1, append(append([]string{}, peerLabels...), reason, code, subcode)...)
+}
+
+// processPeerGracefulRestart writes the graceful restart state of a peer from the BGP neighbors graceful-restart output.
+func processPeerGracefulRestart(ch chan<- prometheus.Metric, afiSafi string, peer bgpGRPeer, bgpDesc map[string]*prometheus.Desc, peerLabels []string) {
+	localMode, remoteMode := bgpGRMode(peer.LocalGrMode), bgpGRMode(peer.RemoteGrMode)
+	newGauge(ch, bgpDesc["grInfo"], 1, append(append([]string{}, peerLabels...), localMode, remoteMode)...)
+
+	negotiated := (localMode == "helper" || localMode == "restarter") && (remoteMode == "helper" || remoteMode == "restarter")
+	newGauge(ch, bgpDesc["grNegotiated"], boolToFloat(negotiated), peerLabels...)
+	newGauge(ch, bgpDesc["grRestartTimer"], float64(peer.Timers.ReceivedRestartTimer), peerLabels...)
+
+	// bgpd only reports the remaining restart and stale path time while the corresponding timer is running.
+	helperActive := peer.Timers.RestartTimerRemaining != nil || peer.Timers.StalePathTimerRemaining != nil
+
+	if af, ok := lookupAFISAFI(peer.AddressFamilies, afiSafi); ok {
+		newGauge(ch, bgpDesc["grStalePathTimer"], float64(af.Timers.StalePathTimer), peerLabels...)
+		helperActive = helperActive || af.Timers.StalePathTimerRemaining != nil
+	}
+	newGauge(ch, bgpDesc["grHelperActive"], boolToFloat(helperActive), peerLabels...)
+}
+
+// bgpGRMode normalises a graceful restart mode of bgpd, such as "Helper*" where the asterisk denotes the mode
+// is inherited from the global configuration.
+func bgpGRMode(mode string) string {
+	mode = strings.ToLower(strings.TrimSuffix(mode, "*"))
+	switch mode {
+	case "restart":
+		return "restarter"
+	case "disable":
+		return "disabled"
+	}
+	return mode
+}
+
 func loadPrefixFilter(path string) ([]string, error) {
 	f, err := os.Open(path)
 	if err != nil {
@@ -536,6 +853,387 @@ func getPeerPrefixPresence(ch chan<- prometheus.Metric, wg *sync.WaitGroup, AFI
 	processPeerPrefixPresence(ch, bgpDesc, receivedSet, advertisedSet, prefixes, peerLabels)
 }
 
+func getPeerPrefixLengths(ch chan<- prometheus.Metric, wg *sync.WaitGroup, AFI string, SAFI string, vrfName string, neighbor string, logger *slog.Logger, bgpDesc map[string]*prometheus.Desc, peerLabels ...string) {
+	defer wg.Done()
+
+	type direction struct {
+		cmd, key, descKey string
+	}
+	directions := []direction{{cmd: "routes", key: "routes", descKey: "prefixLengthRcvd"}}
+	if *bgpPrefixLengthAdvertised {
+		directions = append(directions, direction{cmd: "advertised-routes", key: "advertisedRoutes", descKey: "prefixLengthAdv"})
+	}
+
+	for _, d := range directions {
+		var cmd string
+		if strings.ToLower(vrfName) == "default" {
+			cmd = fmt.Sprintf("show bgp  %s %s neighbors %s %s json", strings.ToLower(AFI), strings.ToLower(SAFI), neighbor, d.cmd)
+		} else {
+			cmd = fmt.Sprintf("show bgp vrf %s %s %s neighbors %s %s json", vrfName, strings.ToLower(AFI), strings.ToLower(SAFI), neighbor, d.cmd)
+		}
+
+		output, err := executeBGPCommand(cmd)
+		if err != nil {
+			logger.Error("get neighbor routes for prefix lengths failed", "afi", AFI, "safi", SAFI, "vrf", vrfName, "neighbor", neighbor, "err", err)
+			return
+		}
+		lengths, err := countPrefixLengths(bytes.NewReader(output), d.key)
+		if err != nil {
+			logger.Error("get neighbor routes for prefix lengths failed", "afi", AFI, "safi", SAFI, "vrf", vrfName, "neighbor", neighbor, "err", err)
+			return
+		}
+		ch <- prefixLengthHistogram(AFI, lengths).metric(bgpDesc[d.descKey], peerLabels...)
+	}
+}
+
+// bgpPrefixLengthPeerSelected returns whether the prefix lengths of a peer are selected by one of the entries of
+// --collector.bgp.prefix-length.peers, which are either vrf/peer or a peer matching in every VRF.
+func bgpPrefixLengthPeerSelected(entries []string, vrfName string, peer string) bool {
+	for _, entry := range entries {
+		if vrf, entryPeer, ok := strings.Cut(entry, "/"); ok {
+			if strings.EqualFold(vrf, vrfName) && entryPeer == peer {
+				return true
+			}
+		} else if entry == peer {
+			return true
+		}
+	}
+	return false
+}
+
+// prefixLengthHistogram returns the histogram of the prefix lengths counted by countPrefixLengths.
+func prefixLengthHistogram(AFI string, lengths map[int]float64) *constHistogram {
+	buckets, ok := bgpPrefixLengthBuckets[strings.ToLower(AFI)]
+	if !ok {
+		buckets = bgpPrefixLengthBuckets["ipv4"]
+	}
+	h := newConstHistogram(buckets)
+	for length, count := range lengths {
+		h.observeN(float64(length), uint64(count))
+	}
+	return h
+}
+
+// countPrefixLengths counts the prefixes of the routes object under key by prefix length.
+func countPrefixLengths(r io.Reader, key string) (map[int]float64, error) {
+	// skip is reused to hold the route currently being skipped.
+	var skip json.RawMessage
+	lengths := make(map[int]float64)
+	err := walkBGPRoutes(r, key, func(network string, dec *json.Decoder) error {
+		if err := dec.Decode(&skip); err != nil {
+			return err
+		}
+		if prefix, err := netip.ParsePrefix(network); err == nil {
+			lengths[prefix.Bits()]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lengths, nil
+}
+
+// walkBGPRoutes calls fn with the network of each route of the routes object under key, and the decoder positioned
+// at the route, which fn must consume. The output is walked token by token, so a full table is never held in memory.
+func walkBGPRoutes(r io.Reader, key string, fn func(network string, dec *json.Decoder) error) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	// skip is reused to hold the value currently being skipped.
+	var skip json.RawMessage
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if tok != key {
+			if err := dec.Decode(&skip); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := expectDelim(dec, '{'); err != nil {
+			return err
+		}
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			if err := fn(fmt.Sprint(tok), dec); err != nil {
+				return err
+			}
+		}
+		if err := expectDelim(dec, '}'); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("expected %q, got %v", delim, tok)
+	}
+	return nil
+}
+
+func getPeerRPKIValidation(ch chan<- prometheus.Metric, wg *sync.WaitGroup, AFI string, SAFI string, vrfName string, neighbor string, logger *slog.Logger, bgpDesc map[string]*prometheus.Desc, peerLabels ...string) {
+	defer wg.Done()
+	defer func() { <-bgpRPKIValidationFetches }()
+
+	var cmd string
+	if strings.ToLower(vrfName) == "default" {
+		cmd = fmt.Sprintf("show bgp  %s %s neighbors %s routes json", strings.ToLower(AFI), strings.ToLower(SAFI), neighbor)
+	} else {
+		cmd = fmt.Sprintf("show bgp vrf %s %s %s neighbors %s routes json", vrfName, strings.ToLower(AFI), strings.ToLower(SAFI), neighbor)
+	}
+
+	output, err := executeBGPCommand(cmd)
+	if err != nil {
+		logger.Error("get neighbor received routes for rpki validation failed", "afi", AFI, "safi", SAFI, "vrf", vrfName, "neighbor", neighbor, "err", err)
+		return
+	}
+	if err := processPeerRPKIValidation(ch, bytes.NewReader(output), bgpDesc, peerLabels); err != nil {
+		logger.Error("get neighbor received routes for rpki validation failed", "afi", AFI, "safi", SAFI, "vrf", vrfName, "neighbor", neighbor, "err", err)
+	}
+}
+
+// processPeerRPKIValidation counts the prefixes received from a peer by the rpkiValidationState of their
+// paths, as printed by bgpd's bgp_rpki_validation2str. bgpd only adds rpkiValidationState to a path when the
+// rpki module is loaded, so all counts are 0 otherwise.
+func processPeerRPKIValidation(ch chan<- prometheus.Metric, r io.Reader, bgpDesc map[string]*prometheus.Desc, peerLabels []string) error {
+	stateLabels := map[string]string{"valid": "valid", "invalid": "invalid", "not found": "notfound"}
+	states := map[string]float64{"valid": 0, "invalid": 0, "notfound": 0}
+	invalidBest := 0.0
+
+	err := walkBGPRoutes(r, "routes", func(_ string, dec *json.Decoder) error {
+		var paths []bgpRPKIPath
+		if err := dec.Decode(&paths); err != nil {
+			return err
+		}
+		for _, path := range paths {
+			state, ok := stateLabels[strings.ToLower(path.RPKIValidationState)]
+			if !ok {
+				continue
+			}
+			states[state]++
+			if state == "invalid" && path.Bestpath {
+				invalidBest++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for state, count := range states {
+		newGauge(ch, bgpDesc["rpkiPrefixes"], count, append(append([]string{}, peerLabels...), state)...)
+	}
+	newGauge(ch, bgpDesc["rpkiInvalidBest"], invalidBest, peerLabels...)
+	return nil
+}
+
+type bgpRPKIPath struct {
+	Bestpath            bool   `json:"bestpath"`
+	RPKIValidationState string `json:"rpkiValidationState"`
+}
+
+func getBGPUpdateGroups(ch chan<- prometheus.Metric, wg *sync.WaitGroup, AFI string, SAFI string, vrfName string, procLabels []string, logger *slog.Logger, bgpDesc map[string]*prometheus.Desc) {
+	defer wg.Done()
+
+	var cmd string
+	if strings.ToLower(vrfName) == "default" {
+		cmd = fmt.Sprintf("show bgp %s %s update-groups json", strings.ToLower(AFI), strings.ToLower(SAFI))
+	} else {
+		cmd = fmt.Sprintf("show bgp vrf %s %s %s update-groups json", vrfName, strings.ToLower(AFI), strings.ToLower(SAFI))
+	}
+
+	output, err := executeBGPCommand(cmd)
+	if err != nil {
+		logger.Error("get bgp update groups failed", "afi", AFI, "safi", SAFI, "vrf", vrfName, "err", err)
+		return
+	}
+	if err := processBGPUpdateGroups(ch, output, procLabels, bgpDesc); err != nil {
+		logger.Error("get bgp update groups failed", "afi", AFI, "safi", SAFI, "vrf", vrfName, "err", err)
+	}
+}
+
+// processBGPUpdateGroups writes the update group and subgroup metrics of the update-groups output, which is keyed
+// by update group ID.
+func processBGPUpdateGroups(ch chan<- prometheus.Metric, jsonUpdateGroups []byte, procLabels []string, bgpDesc map[string]*prometheus.Desc) error {
+	var updateGroups map[string]bgpUpdateGroup
+	if err := json.Unmarshal(jsonUpdateGroups, &updateGroups); err != nil {
+		return err
+	}
+
+	subgroups := 0.0
+	for groupID, group := range updateGroups {
+		for _, subgroup := range group.SubGroups {
+			subgroups++
+			labels := append(append([]string{}, procLabels...), groupID, strconv.FormatUint(subgroup.ID, 10))
+
+			// bgpd misspells the queue length as qeueueLen.
+			queueLen := subgroup.PacketQueueInfo.QeueueLen
+			if subgroup.PacketQueueInfo.QueueLen != nil {
+				queueLen = *subgroup.PacketQueueInfo.QueueLen
+			}
+
+			newGauge(ch, bgpDesc["subgroupPeers"], float64(len(subgroup.Peers)), labels...)
+			newCounter(ch, bgpDesc["subgroupSplits"], float64(subgroup.Statistics.SplitEvents), labels...)
+			newCounter(ch, bgpDesc["subgroupMerges"], float64(subgroup.Statistics.MergeEvents), labels...)
+			newGauge(ch, bgpDesc["subgroupQueue"], float64(queueLen), labels...)
+			newGauge(ch, bgpDesc["subgroupAdjOut"], float64(subgroup.AdjListCount), labels...)
+		}
+	}
+
+	newGauge(ch, bgpDesc["updateGroups"], float64(len(updateGroups)), procLabels...)
+	newGauge(ch, bgpDesc["updateSubgroups"], subgroups, procLabels...)
+	return nil
+}
+
+type bgpUpdateGroup struct {
+	SubGroups []bgpUpdateSubgroup `json:"subGroup"`
+}
+
+type bgpUpdateSubgroup struct {
+	ID              uint64                     `json:"subGroupId"`
+	Statistics      bgpUpdateSubgroupStats     `json:"statistics"`
+	PacketQueueInfo bgpUpdateSubgroupQueueInfo `json:"packetQueueInfo"`
+	AdjListCount    uint64                     `json:"adjListCount"`
+	Peers           []string                   `json:"peers"`
+}
+
+type bgpUpdateSubgroupStats struct {
+	MergeEvents uint64 `json:"mergeEvents"`
+	SplitEvents uint64 `json:"splitEvents"`
+}
+
+type bgpUpdateSubgroupQueueInfo struct {
+	QeueueLen uint64  `json:"qeueueLen"`
+	QueueLen  *uint64 `json:"queueLen"`
+}
+
+func getBGPDampening(ch chan<- prometheus.Metric, wg *sync.WaitGroup, AFI string, SAFI string, vrfName string, procLabels []string, peerLabels map[string][]string, logger *slog.Logger, bgpDesc map[string]*prometheus.Desc) {
+	defer wg.Done()
+
+	var cmdParameters, cmdFlapStats string
+	if strings.ToLower(vrfName) == "default" {
+		cmdParameters = fmt.Sprintf("show bgp %s %s dampening parameters json", strings.ToLower(AFI), strings.ToLower(SAFI))
+		cmdFlapStats = fmt.Sprintf("show bgp %s %s dampening flap-statistics json", strings.ToLower(AFI), strings.ToLower(SAFI))
+	} else {
+		cmdParameters = fmt.Sprintf("show bgp vrf %s %s %s dampening parameters json", vrfName, strings.ToLower(AFI), strings.ToLower(SAFI))
+		cmdFlapStats = fmt.Sprintf("show bgp vrf %s %s %s dampening flap-statistics json", vrfName, strings.ToLower(AFI), strings.ToLower(SAFI))
+	}
+
+	output, err := executeBGPCommand(cmdParameters)
+	if err != nil {
+		logger.Error("get bgp dampening parameters failed", "afi", AFI, "safi", SAFI, "vrf", vrfName, "err", err)
+		return
+	}
+	// bgpd replies with plain text instead of JSON when dampening is not enabled for the address family.
+	if !json.Valid(output) {
+		return
+	}
+	if err := processBGPDampeningParameters(ch, output, procLabels, bgpDesc); err != nil {
+		logger.Error("get bgp dampening parameters failed", "afi", AFI, "safi", SAFI, "vrf", vrfName, "err", err)
+		return
+	}
+
+	output, err = executeBGPCommand(cmdFlapStats)
+	if err != nil {
+		logger.Error("get bgp dampening flap statistics failed", "afi", AFI, "safi", SAFI, "vrf", vrfName, "err", err)
+		return
+	}
+	if err := processBGPDampenedPaths(ch, output, procLabels, peerLabels, bgpDesc); err != nil {
+		logger.Error("get bgp dampening flap statistics failed", "afi", AFI, "safi", SAFI, "vrf", vrfName, "err", err)
+	}
+}
+
+func processBGPDampeningParameters(ch chan<- prometheus.Metric, jsonParameters []byte, procLabels []string, bgpDesc map[string]*prometheus.Desc) error {
+	var params bgpDampeningParameters
+	if err := json.Unmarshal(jsonParameters, &params); err != nil {
+		return err
+	}
+
+	newGauge(ch, bgpDesc["dampHalfLife"], float64(params.HalfLife), procLabels...)
+	newGauge(ch, bgpDesc["dampReuse"], float64(params.ReusePenalty), procLabels...)
+	newGauge(ch, bgpDesc["dampSuppress"], float64(params.SuppressPenalty), procLabels...)
+	newGauge(ch, bgpDesc["dampMaxSuppressTime"], float64(params.MaxSuppressTime), procLabels...)
+	newGauge(ch, bgpDesc["dampMaxPenalty"], float64(params.MaxSuppressPenalty), procLabels...)
+	return nil
+}
+
+// processBGPDampenedPaths counts the damped and history paths of the dampening flap-statistics output, which
+// lists every path with route flap dampening information, in total and for each of the peers in peerLabels.
+func processBGPDampenedPaths(ch chan<- prometheus.Metric, jsonFlapStats []byte, procLabels []string, peerLabels map[string][]string, bgpDesc map[string]*prometheus.Desc) error {
+	var flapStats bgpDampeningRoutes
+	if err := json.Unmarshal(jsonFlapStats, &flapStats); err != nil {
+		return err
+	}
+
+	states := []string{"damped", "history"}
+	total := map[string]float64{"damped": 0, "history": 0}
+	perPeer := make(map[string]map[string]float64, len(peerLabels))
+	for peer := range peerLabels {
+		perPeer[peer] = map[string]float64{"damped": 0, "history": 0}
+	}
+
+	for _, paths := range flapStats.Routes {
+		for _, path := range paths {
+			peer := path.PeerHost
+			if peer == "" {
+				peer = path.PeerID
+			}
+			for state, set := range map[string]bool{"damped": path.Damped, "history": path.History} {
+				if !set {
+					continue
+				}
+				total[state]++
+				if _, ok := perPeer[peer]; ok {
+					perPeer[peer][state]++
+				}
+			}
+		}
+	}
+
+	for _, state := range states {
+		newGauge(ch, bgpDesc["dampPaths"], total[state], append(append([]string{}, procLabels...), state)...)
+		for peer, counts := range perPeer {
+			newGauge(ch, bgpDesc["peerDampPaths"], counts[state], append(append([]string{}, peerLabels[peer]...), state)...)
+		}
+	}
+	return nil
+}
+
+type bgpDampeningParameters struct {
+	HalfLife           uint32 `json:"halfLifeSecs"`
+	ReusePenalty       uint32 `json:"reusePenalty"`
+	SuppressPenalty    uint32 `json:"suppressPenalty"`
+	MaxSuppressTime    uint32 `json:"maxSuppressTimeSecs"`
+	MaxSuppressPenalty uint32 `json:"maxSuppressPenalty"`
+}
+
+type bgpDampeningRoutes struct {
+	Routes map[string][]bgpDampeningPath `json:"routes"`
+}
+
+type bgpDampeningPath struct {
+	PeerHost string `json:"peerHost"`
+	PeerID   string `json:"peerId"`
+	Damped   bool   `json:"damped"`
+	History  bool   `json:"history"`
+}
+
 type bgpProcess struct {
 	RouterID        string
 	AS              uint32
@@ -563,6 +1261,35 @@ type bgpAdvertisedRoutes struct {
 	TotalPrefixCounter uint32 `json:"totalPrefixCounter"`
 }
 
+// bgpNeighbors holds the outputs describing the BGP neighbors of all address families, retrieved once per
+// scrape and only when an option using them is enabled.
+type bgpNeighbors struct {
+	desc            map[string]bgpVRF
+	nextHop         map[string]bgpNextHop
+	gracefulRestart map[string]bgpGRVRF
+}
+
+func getBGPNeighbors() (bgpNeighbors, error) {
+	var neighbors bgpNeighbors
+	var err error
+	if *bgpPeerTypes || *bgpPeerDescs || *bgpPeerGroups || *bgpAcceptedFilteredPrefixes || *bgpPeerDetails || *bgpPeerLastReset || *bgpMaxPrefix {
+		if neighbors.desc, err = getBGPPeerDesc(); err != nil {
+			return neighbors, err
+		}
+	}
+	if *bgpNextHopInterface {
+		if neighbors.nextHop, err = getBGPNexthop(); err != nil {
+			return neighbors, err
+		}
+	}
+	if *bgpGracefulRestart {
+		if neighbors.gracefulRestart, err = getBGPGracefulRestart(); err != nil {
+			return neighbors, err
+		}
+	}
+	return neighbors, nil
+}
+
 func getBGPPeerDesc() (map[string]bgpVRF, error) {
 	output, err := executeBGPCommand("show bgp vrf all neighbors json")
 	if err != nil {
@@ -571,6 +1298,22 @@ func getBGPPeerDesc() (map[string]bgpVRF, error) {
 	return processBGPPeerDesc(output)
 }
 
+func getBGPGracefulRestart() (map[string]bgpGRVRF, error) {
+	output, err := executeBGPCommand("show bgp vrf all neighbors graceful-restart json")
+	if err != nil {
+		return nil, err
+	}
+	return processBGPGracefulRestart(output)
+}
+
+func processBGPGracefulRestart(output []byte) (map[string]bgpGRVRF, error) {
+	vrfMap := make(map[string]bgpGRVRF)
+	if err := json.Unmarshal(output, &vrfMap); err != nil {
+		return nil, err
+	}
+	return vrfMap, nil
+}
+
 func processBGPPeerDesc(output []byte) (map[string]bgpVRF, error) {
 	vrfMap := make(map[string]bgpVRF)
 	if err := json.Unmarshal([]byte(output), &vrfMap); err != nil {
@@ -611,6 +1354,88 @@ func (vrf *bgpVRF) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+type bgpGRVRF struct {
+	Peers map[string]bgpGRPeer
+}
+
+func (vrf *bgpGRVRF) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	vrf.Peers = make(map[string]bgpGRPeer)
+	for k, v := range raw {
+		// Peers are objects, unlike the vrfId and vrfName keys.
+		if !bytes.HasPrefix(bytes.TrimSpace(v), []byte("{")) {
+			continue
+		}
+		var peer bgpGRPeer
+		if err := json.Unmarshal(v, &peer); err != nil {
+			return err
+		}
+		vrf.Peers[k] = peer
+	}
+	return nil
+}
+
+type bgpGRPeer struct {
+	LocalGrMode     string
+	RemoteGrMode    string
+	Timers          bgpGRTimers
+	AddressFamilies map[string]bgpGRAFISAFI
+}
+
+// UnmarshalJSON decodes a peer of the graceful-restart output, where each address family is a key of the peer,
+// for example "ipv4Unicast".
+func (peer *bgpGRPeer) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	peer.AddressFamilies = make(map[string]bgpGRAFISAFI)
+	for k, v := range raw {
+		var err error
+		switch k {
+		case "localGrMode":
+			err = json.Unmarshal(v, &peer.LocalGrMode)
+		case "remoteGrMode":
+			err = json.Unmarshal(v, &peer.RemoteGrMode)
+		case "timers":
+			err = json.Unmarshal(v, &peer.Timers)
+		default:
+			// Address families are objects, unlike keys such as neighborAddr, rBit and nBit.
+			if !bytes.HasPrefix(bytes.TrimSpace(v), []byte("{")) {
+				continue
+			}
+			var af bgpGRAFISAFI
+			if err = json.Unmarshal(v, &af); err == nil {
+				peer.AddressFamilies[k] = af
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type bgpGRTimers struct {
+	ReceivedRestartTimer    uint32  `json:"receivedRestartTimer"`
+	RestartTimerRemaining   *uint32 `json:"restartTimerRemaining"`
+	StalePathTimerRemaining *uint32 `json:"stalePathTimerRemaining"`
+}
+
+type bgpGRAFISAFI struct {
+	Timers bgpGRAFISAFITimers `json:"timers"`
+}
+
+type bgpGRAFISAFITimers struct {
+	StalePathTimer          uint32  `json:"stalePathTimer"`
+	StalePathTimerRemaining *uint32 `json:"stalePathTimerRemaining"`
+}
+
 type bgpVRF struct {
 	ID           int                    `json:"vrfId"`
 	Name         string                 `json:"vrfName"`
@@ -618,14 +1443,43 @@ type bgpVRF struct {
 }
 
 type bgpNeighbor struct {
-	Desc              string                        `json:"nbrDesc"`
-	PeerGroup         string                        `json:"peerGroup"`
-	AddressFamilyInfo map[string]bgpNeighborAFISAFI `json:"addressFamilyInfo"`
+	Desc                   string                        `json:"nbrDesc"`
+	PeerGroup              string                        `json:"peerGroup"`
+	AddressFamilyInfo      map[string]bgpNeighborAFISAFI `json:"addressFamilyInfo"`
+	ConnectionsEstablished uint32                        `json:"connectionsEstablished"`
+	ConnectionsDropped     uint32                        `json:"connectionsDropped"`
+	HoldTimeMsecs          uint32                        `json:"bgpTimerHoldTimeMsecs"`
+	KeepAliveIntervalMsecs uint32                        `json:"bgpTimerKeepAliveIntervalMsecs"`
+	LastReadMsecs          *uint64                       `json:"bgpTimerLastRead"`
+	LastWriteMsecs         *uint64                       `json:"bgpTimerLastWrite"`
+	MessageStats           *bgpMessageStats              `json:"messageStats"`
+	LastReset              string                        `json:"lastReset"`
+	LastResetDueTo         string                        `json:"lastResetDueTo"`
+	LastResetMsecs         *uint64                       `json:"lastResetTimerMsecs"`
+	LastNotificationReason string                        `json:"lastNotificationReason"`
+}
+
+type bgpMessageStats struct {
+	DepthInq          uint64 `json:"depthInq"`
+	DepthOutq         uint64 `json:"depthOutq"`
+	OpensSent         uint64 `json:"opensSent"`
+	OpensRecv         uint64 `json:"opensRecv"`
+	NotificationsSent uint64 `json:"notificationsSent"`
+	NotificationsRecv uint64 `json:"notificationsRecv"`
+	UpdatesSent       uint64 `json:"updatesSent"`
+	UpdatesRecv       uint64 `json:"updatesRecv"`
+	KeepalivesSent    uint64 `json:"keepalivesSent"`
+	KeepalivesRecv    uint64 `json:"keepalivesRecv"`
+	RouteRefreshSent  uint64 `json:"routeRefreshSent"`
+	RouteRefreshRecv  uint64 `json:"routeRefreshRecv"`
 }
 
 type bgpNeighborAFISAFI struct {
-	AcceptedPrefixCounter uint32 `json:"acceptedPrefixCounter"`
-	SentPrefixCounter     uint32 `json:"sentPrefixCounter"`
+	AcceptedPrefixCounter             uint32  `json:"acceptedPrefixCounter"`
+	SentPrefixCounter                 uint32  `json:"sentPrefixCounter"`
+	PrefixAllowedMax                  *uint32 `json:"prefixAllowedMax"`
+	PrefixAllowedMaxWarning           bool    `json:"prefixAllowedMaxWarning"`
+	PrefixAllowedRestartIntervalMsecs uint64  `json:"prefixAllowedRestartIntervalMsecs"`
 }
 
 type bgpNextHopInterfaces struct {
@@ -1,23 +1,26 @@
 package collector
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-func runBGPSummaryTest(t *testing.T, fixture string, afi string, processFn func(chan<- prometheus.Metric, []byte, string, string, *slog.Logger, map[string]*prometheus.Desc, []string) error, getDesc func() map[string]*prometheus.Desc, expected map[string]float64) {
+func runBGPSummaryTest(t *testing.T, fixture string, afi string, processFn func(chan<- prometheus.Metric, []byte, string, string, *slog.Logger, map[string]*prometheus.Desc, []string, bgpNeighbors, *stateObservations) error, getDesc func() map[string]*prometheus.Desc, expected map[string]float64) {
 	// load the raw JSON
 	data := readTestFixture(t, fixture)
 
 	// enough buffer for instance=0 plus instances 1,2
 	ch := make(chan prometheus.Metric, len(expected)*3)
 
-	if err := processFn(ch, data, afi, "", nil, getDesc(), nil); err != nil {
+	if err := processFn(ch, data, afi, "", nil, getDesc(), nil, bgpNeighbors{}, nil); err != nil {
 		t.Errorf("error calling processFn %s: %s", afi, err)
 	}
 	close(ch)
@@ -125,6 +128,65 @@ func TestProcessBgpL2vpnEvpnSummary(t *testing.T) {
 	compareMetrics(t, gotMetrics, expected)
 }
 
+func TestProcessBGPSummarySAFI(t *testing.T) {
+	expected := map[string]float64{
+		"frr_bgp_rib_count_total{afi=ipv4,local_as=64512,safi=vpn,vrf=default}":                                                      12.0,
+		"frr_bgp_rib_memory_bytes{afi=ipv4,local_as=64512,safi=vpn,vrf=default}":                                                     2208.0,
+		"frr_bgp_peers_count_total{afi=ipv4,local_as=64512,safi=vpn,vrf=default}":                                                    1.0,
+		"frr_bgp_peers_memory_bytes{afi=ipv4,local_as=64512,safi=vpn,vrf=default}":                                                   19968.0,
+		"frr_bgp_peer_groups_count_total{afi=ipv4,local_as=64512,safi=vpn,vrf=default}":                                              0.0,
+		"frr_bgp_peer_groups_memory_bytes{afi=ipv4,local_as=64512,safi=vpn,vrf=default}":                                             0.0,
+		"frr_bgp_peer_message_received_total{afi=ipv4,local_as=64512,peer=192.168.0.10,peer_as=64512,safi=vpn,vrf=default}":          250.0,
+		"frr_bgp_peer_message_sent_total{afi=ipv4,local_as=64512,peer=192.168.0.10,peer_as=64512,safi=vpn,vrf=default}":              240.0,
+		"frr_bgp_peer_prefixes_received_count_total{afi=ipv4,local_as=64512,peer=192.168.0.10,peer_as=64512,safi=vpn,vrf=default}":   8.0,
+		"frr_bgp_peer_prefixes_advertised_count_total{afi=ipv4,local_as=64512,peer=192.168.0.10,peer_as=64512,safi=vpn,vrf=default}": 4.0,
+		"frr_bgp_peer_state{afi=ipv4,local_as=64512,peer=192.168.0.10,peer_as=64512,safi=vpn,vrf=default}":                           1.0,
+		"frr_bgp_peer_uptime_seconds{afi=ipv4,local_as=64512,peer=192.168.0.10,peer_as=64512,safi=vpn,vrf=default}":                  300.0,
+	}
+
+	ch := make(chan prometheus.Metric, len(expected)*3)
+	if err := processBGPSummary(ch, readTestFixture(t, "show_bgp_vrf_all_ipv4_vpn_summary.json"), "ipv4", "vpn", nil, getBGPDesc(), nil, bgpNeighbors{}, nil); err != nil {
+		t.Errorf("error calling processBGPSummary ipv4 vpn: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestBGPSAFIFromKey(t *testing.T) {
+	for _, tc := range []struct {
+		afi, key, expected string
+	}{
+		{"ipv4", "ipv4Unicast", "unicast"},
+		{"ipv4", "ipv4Multicast", "multicast"},
+		{"ipv4", "ipv4Vpn", "vpn"},
+		{"ipv6", "ipv6LabeledUnicast", "labeled-unicast"},
+		{"ipv4", "ipv4Flowspec", "flowspec"},
+		{"l2vpn", "l2VpnEvpn", "evpn"},
+	} {
+		if got := bgpSAFIFromKey(tc.afi, tc.key); got != tc.expected {
+			t.Errorf("bgpSAFIFromKey(%q, %q) = %q, want %q", tc.afi, tc.key, got, tc.expected)
+		}
+	}
+}
+
+func TestParseBGPAFISAFIs(t *testing.T) {
+	got, err := parseBGPAFISAFIs("ipv4/unicast, ipv4/vpn,IPv6/labeled-unicast")
+	if err != nil {
+		t.Fatalf("parseBGPAFISAFIs returned error: %v", err)
+	}
+	expected := []bgpAFISAFI{{afi: "ipv4", safi: "unicast"}, {afi: "ipv4", safi: "vpn"}, {afi: "ipv6", safi: "labeled-unicast"}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("parseBGPAFISAFIs() = %v, want %v", got, expected)
+	}
+
+	for _, invalid := range []string{"", "ipv5", "ipv4/anycast", "ipv4,ipv4/vpn", "ipv6/vpn,ipv6", "ipv4/vpn,ipv4/vpn"} {
+		if _, err := parseBGPAFISAFIs(invalid); err == nil {
+			t.Errorf("parseBGPAFISAFIs(%q) should return error", invalid)
+		}
+	}
+}
+
 func TestProcessBGPPeerDesc(t *testing.T) {
 	expectedOutput := map[string]bgpVRF{
 		"default": {
@@ -154,6 +216,315 @@ func TestProcessBGPPeerDesc(t *testing.T) {
 	}
 }
 
+func TestProcessPeerDetails(t *testing.T) {
+	expected := map[string]float64{}
+	for peer, values := range map[string]map[string]float64{
+		"10.1.1.10": {
+			"frr_bgp_peer_connections_established_total": 2.0,
+			"frr_bgp_peer_connections_dropped_total":     1.0,
+			"frr_bgp_peer_hold_time_seconds":             9.0,
+			"frr_bgp_peer_keepalive_interval_seconds":    3.0,
+			"frr_bgp_peer_input_queue_depth":             0.0,
+			"frr_bgp_peer_output_queue_depth":            2.0,
+			"frr_bgp_peer_last_read_seconds":             1.0,
+			"frr_bgp_peer_last_write_seconds":            2.5,
+		},
+		"10.1.1.11": {
+			"frr_bgp_peer_connections_established_total": 0.0,
+			"frr_bgp_peer_connections_dropped_total":     0.0,
+			"frr_bgp_peer_hold_time_seconds":             0.0,
+			"frr_bgp_peer_keepalive_interval_seconds":    0.0,
+			"frr_bgp_peer_input_queue_depth":             0.0,
+			"frr_bgp_peer_output_queue_depth":            0.0,
+		},
+	} {
+		for name, value := range values {
+			expected[fmt.Sprintf("%s{afi=ipv4,local_as=64512,peer=%s,peer_as=64513,safi=unicast,vrf=default}", name, peer)] = value
+		}
+	}
+	for _, msg := range []struct {
+		msgType        string
+		received, sent float64
+	}{
+		{"keepalive", 282, 280},
+		{"notification", 0, 1},
+		{"open", 2, 2},
+		{"route_refresh", 1, 0},
+		{"update", 12, 10},
+	} {
+		for _, peer := range []string{"10.1.1.10", "10.1.1.11"} {
+			labels := fmt.Sprintf("{afi=ipv4,local_as=64512,message_type=%s,peer=%s,peer_as=64513,safi=unicast,vrf=default}", msg.msgType, peer)
+			received, sent := msg.received, msg.sent
+			if peer == "10.1.1.11" {
+				received, sent = 0, 0
+			}
+			expected["frr_bgp_peer_message_type_received_total"+labels] = received
+			expected["frr_bgp_peer_message_type_sent_total"+labels] = sent
+		}
+	}
+
+	peerDesc, err := processBGPPeerDesc(readTestFixture(t, "show_bgp_vrf_all_neighbors_detail.json"))
+	if err != nil {
+		t.Fatalf("error calling processBGPPeerDesc: %s", err)
+	}
+
+	ch := make(chan prometheus.Metric, len(expected)*3)
+	for _, peer := range []string{"10.1.1.10", "10.1.1.11"} {
+		processPeerDetails(ch, peerDesc["default"].BGPNeighbors[peer], getBGPDesc(), []string{"default", "ipv4", "unicast", "64512", peer, "64513"})
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestProcessPeerLastReset(t *testing.T) {
+	expected := map[string]float64{
+		"frr_bgp_peer_last_reset_info{afi=ipv4,local_as=64512,notification_code=Hold Timer Expired,notification_subcode=Unspecific,peer=10.1.1.10,peer_as=64513,reason=Notification received,safi=unicast,vrf=default}": 1.0,
+		"frr_bgp_peer_last_reset_info{afi=ipv4,local_as=64512,notification_code=,notification_subcode=,peer=10.1.1.11,peer_as=64513,reason=Waiting for peer OPEN,safi=unicast,vrf=default}":                             1.0,
+		"frr_bgp_peer_last_reset_seconds{afi=ipv4,local_as=64512,peer=10.1.1.10,peer_as=64513,safi=unicast,vrf=default}":                                                                                                847.5,
+		"frr_bgp_peer_last_reset_seconds{afi=ipv4,local_as=64512,peer=10.1.1.11,peer_as=64513,safi=unicast,vrf=default}":                                                                                                120.0,
+	}
+
+	peerDesc, err := processBGPPeerDesc(readTestFixture(t, "show_bgp_vrf_all_neighbors_detail.json"))
+	if err != nil {
+		t.Fatalf("error calling processBGPPeerDesc: %s", err)
+	}
+
+	ch := make(chan prometheus.Metric, len(expected)*3)
+	for _, peer := range []string{"10.1.1.10", "10.1.1.11"} {
+		processPeerLastReset(ch, peerDesc["default"].BGPNeighbors[peer], getBGPDesc(), []string{"default", "ipv4", "unicast", "64512", peer, "64513"})
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestProcessPeerRPKIValidation(t *testing.T) {
+	for _, tc := range []struct {
+		fixture  string
+		expected map[string]float64
+	}{
+		{
+			fixture: "show_bgp_ipv4_unicast_neighbors_routes_rpki.json",
+			expected: map[string]float64{
+				"frr_bgp_peer_rpki_prefixes{afi=ipv4,local_as=64512,peer=192.168.0.2,peer_as=64513,safi=unicast,state=valid,vrf=default}":    1.0,
+				"frr_bgp_peer_rpki_prefixes{afi=ipv4,local_as=64512,peer=192.168.0.2,peer_as=64513,safi=unicast,state=invalid,vrf=default}":  2.0,
+				"frr_bgp_peer_rpki_prefixes{afi=ipv4,local_as=64512,peer=192.168.0.2,peer_as=64513,safi=unicast,state=notfound,vrf=default}": 2.0,
+				"frr_bgp_peer_rpki_invalid_best_prefixes{afi=ipv4,local_as=64512,peer=192.168.0.2,peer_as=64513,safi=unicast,vrf=default}":   1.0,
+			},
+		},
+		{
+			// Without the rpki module loaded, paths carry no rpkiValidationState.
+			fixture: "show_bgp_ipv4_unicast_neighbors_routes.json",
+			expected: map[string]float64{
+				"frr_bgp_peer_rpki_prefixes{afi=ipv4,local_as=64512,peer=192.168.0.2,peer_as=64513,safi=unicast,state=valid,vrf=default}":    0.0,
+				"frr_bgp_peer_rpki_prefixes{afi=ipv4,local_as=64512,peer=192.168.0.2,peer_as=64513,safi=unicast,state=invalid,vrf=default}":  0.0,
+				"frr_bgp_peer_rpki_prefixes{afi=ipv4,local_as=64512,peer=192.168.0.2,peer_as=64513,safi=unicast,state=notfound,vrf=default}": 0.0,
+				"frr_bgp_peer_rpki_invalid_best_prefixes{afi=ipv4,local_as=64512,peer=192.168.0.2,peer_as=64513,safi=unicast,vrf=default}":   0.0,
+			},
+		},
+	} {
+		ch := make(chan prometheus.Metric, len(tc.expected)*3)
+		if err := processPeerRPKIValidation(ch, bytes.NewReader(readTestFixture(t, tc.fixture)), getBGPDesc(), []string{"default", "ipv4", "unicast", "64512", "192.168.0.2", "64513"}); err != nil {
+			t.Errorf("error calling processPeerRPKIValidation with %s: %s", tc.fixture, err)
+		}
+		close(ch)
+
+		compareMetrics(t, collectMetrics(t, ch), tc.expected)
+	}
+}
+
+func TestProcessPeerGracefulRestart(t *testing.T) {
+	expected := map[string]float64{
+		"frr_bgp_peer_graceful_restart_info{afi=ipv4,local_as=64512,local_mode=helper,peer=10.1.1.10,peer_as=64513,remote_mode=restarter,safi=unicast,vrf=default}":       1.0,
+		"frr_bgp_peer_graceful_restart_negotiated{afi=ipv4,local_as=64512,peer=10.1.1.10,peer_as=64513,safi=unicast,vrf=default}":                                         1.0,
+		"frr_bgp_peer_graceful_restart_restart_timer_seconds{afi=ipv4,local_as=64512,peer=10.1.1.10,peer_as=64513,safi=unicast,vrf=default}":                              90.0,
+		"frr_bgp_peer_graceful_restart_stale_path_timer_seconds{afi=ipv4,local_as=64512,peer=10.1.1.10,peer_as=64513,safi=unicast,vrf=default}":                           360.0,
+		"frr_bgp_peer_graceful_restart_helper_active{afi=ipv4,local_as=64512,peer=10.1.1.10,peer_as=64513,safi=unicast,vrf=default}":                                      1.0,
+		"frr_bgp_peer_graceful_restart_info{afi=ipv4,local_as=64512,local_mode=disabled,peer=10.1.1.11,peer_as=64513,remote_mode=notapplicable,safi=unicast,vrf=default}": 1.0,
+		"frr_bgp_peer_graceful_restart_negotiated{afi=ipv4,local_as=64512,peer=10.1.1.11,peer_as=64513,safi=unicast,vrf=default}":                                         0.0,
+		"frr_bgp_peer_graceful_restart_restart_timer_seconds{afi=ipv4,local_as=64512,peer=10.1.1.11,peer_as=64513,safi=unicast,vrf=default}":                              0.0,
+		"frr_bgp_peer_graceful_restart_stale_path_timer_seconds{afi=ipv4,local_as=64512,peer=10.1.1.11,peer_as=64513,safi=unicast,vrf=default}":                           360.0,
+		"frr_bgp_peer_graceful_restart_helper_active{afi=ipv4,local_as=64512,peer=10.1.1.11,peer_as=64513,safi=unicast,vrf=default}":                                      0.0,
+	}
+
+	peerGR, err := processBGPGracefulRestart(readTestFixture(t, "show_bgp_vrf_all_neighbors_graceful_restart.json"))
+	if err != nil {
+		t.Fatalf("error calling processBGPGracefulRestart: %s", err)
+	}
+
+	ch := make(chan prometheus.Metric, len(expected)*3)
+	for _, peer := range []string{"10.1.1.10", "10.1.1.11"} {
+		processPeerGracefulRestart(ch, "ipv4unicast", peerGR["default"].Peers[peer], getBGPDesc(), []string{"default", "ipv4", "unicast", "64512", peer, "64513"})
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestProcessBGPDampening(t *testing.T) {
+	expected := map[string]float64{
+		"frr_bgp_dampening_half_life_seconds{afi=ipv4,local_as=64512,safi=unicast,vrf=default}":                                       900.0,
+		"frr_bgp_dampening_reuse_penalty{afi=ipv4,local_as=64512,safi=unicast,vrf=default}":                                           750.0,
+		"frr_bgp_dampening_suppress_penalty{afi=ipv4,local_as=64512,safi=unicast,vrf=default}":                                        2000.0,
+		"frr_bgp_dampening_max_suppress_time_seconds{afi=ipv4,local_as=64512,safi=unicast,vrf=default}":                               3600.0,
+		"frr_bgp_dampening_max_suppress_penalty{afi=ipv4,local_as=64512,safi=unicast,vrf=default}":                                    12000.0,
+		"frr_bgp_dampening_paths{afi=ipv4,local_as=64512,safi=unicast,state=damped,vrf=default}":                                      1.0,
+		"frr_bgp_dampening_paths{afi=ipv4,local_as=64512,safi=unicast,state=history,vrf=default}":                                     2.0,
+		"frr_bgp_peer_dampening_paths{afi=ipv4,local_as=64512,peer=192.168.0.2,peer_as=64513,safi=unicast,state=damped,vrf=default}":  1.0,
+		"frr_bgp_peer_dampening_paths{afi=ipv4,local_as=64512,peer=192.168.0.2,peer_as=64513,safi=unicast,state=history,vrf=default}": 1.0,
+		"frr_bgp_peer_dampening_paths{afi=ipv4,local_as=64512,peer=192.168.0.3,peer_as=64514,safi=unicast,state=damped,vrf=default}":  0.0,
+		"frr_bgp_peer_dampening_paths{afi=ipv4,local_as=64512,peer=192.168.0.3,peer_as=64514,safi=unicast,state=history,vrf=default}": 1.0,
+		"frr_bgp_peer_dampening_paths{afi=ipv4,local_as=64512,peer=192.168.0.4,peer_as=64515,safi=unicast,state=damped,vrf=default}":  0.0,
+		"frr_bgp_peer_dampening_paths{afi=ipv4,local_as=64512,peer=192.168.0.4,peer_as=64515,safi=unicast,state=history,vrf=default}": 0.0,
+	}
+
+	procLabels := []string{"default", "ipv4", "unicast", "64512"}
+	peerLabels := map[string][]string{
+		"192.168.0.2": {"default", "ipv4", "unicast", "64512", "192.168.0.2", "64513"},
+		"192.168.0.3": {"default", "ipv4", "unicast", "64512", "192.168.0.3", "64514"},
+		"192.168.0.4": {"default", "ipv4", "unicast", "64512", "192.168.0.4", "64515"},
+	}
+
+	ch := make(chan prometheus.Metric, len(expected)*3)
+	if err := processBGPDampeningParameters(ch, readTestFixture(t, "show_bgp_ipv4_unicast_dampening_parameters.json"), procLabels, getBGPDesc()); err != nil {
+		t.Errorf("error calling processBGPDampeningParameters: %s", err)
+	}
+	if err := processBGPDampenedPaths(ch, readTestFixture(t, "show_bgp_ipv4_unicast_dampening_flap_statistics.json"), procLabels, peerLabels, getBGPDesc()); err != nil {
+		t.Errorf("error calling processBGPDampenedPaths: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestProcessPeerMaxPrefix(t *testing.T) {
+	expected := map[string]float64{
+		"frr_bgp_peer_max_prefix_limit{afi=ipv4,local_as=64512,peer=10.1.1.10,peer_as=64513,safi=unicast,vrf=default}":                    1000.0,
+		"frr_bgp_peer_max_prefix_warning_only{afi=ipv4,local_as=64512,peer=10.1.1.10,peer_as=64513,safi=unicast,vrf=default}":             0.0,
+		"frr_bgp_peer_max_prefix_restart_interval_seconds{afi=ipv4,local_as=64512,peer=10.1.1.10,peer_as=64513,safi=unicast,vrf=default}": 300.0,
+		"frr_bgp_peer_max_prefix_utilisation_ratio{afi=ipv4,local_as=64512,peer=10.1.1.10,peer_as=64513,safi=unicast,vrf=default}":        0.8,
+		"frr_bgp_peer_max_prefix_limit{afi=ipv4,local_as=64512,peer=10.1.1.11,peer_as=64513,safi=unicast,vrf=default}":                    500.0,
+		"frr_bgp_peer_max_prefix_warning_only{afi=ipv4,local_as=64512,peer=10.1.1.11,peer_as=64513,safi=unicast,vrf=default}":             1.0,
+		"frr_bgp_peer_max_prefix_restart_interval_seconds{afi=ipv4,local_as=64512,peer=10.1.1.11,peer_as=64513,safi=unicast,vrf=default}": 0.0,
+		"frr_bgp_peer_max_prefix_utilisation_ratio{afi=ipv4,local_as=64512,peer=10.1.1.11,peer_as=64513,safi=unicast,vrf=default}":        0.0,
+	}
+
+	peerDesc, err := processBGPPeerDesc(readTestFixture(t, "show_bgp_vrf_all_neighbors_detail.json"))
+	if err != nil {
+		t.Fatalf("error calling processBGPPeerDesc: %s", err)
+	}
+
+	ch := make(chan prometheus.Metric, len(expected)*3)
+	// 10.1.1.12 has no maximum-prefix limit configured.
+	for _, peer := range []string{"10.1.1.10", "10.1.1.11", "10.1.1.12"} {
+		processPeerMaxPrefix(ch, "ipv4unicast", peerDesc["default"].BGPNeighbors[peer].AddressFamilyInfo, getBGPDesc(), []string{"default", "ipv4", "unicast", "64512", peer, "64513"})
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestCountPrefixLengths(t *testing.T) {
+	for _, tc := range []struct {
+		fixture, key string
+		expected     map[int]float64
+	}{
+		{"show_bgp_ipv6_unicast_neighbors_routes.json", "routes", map[int]float64{32: 1, 48: 3, 64: 1}},
+		{"show_bgp_ipv4_unicast_neighbors_routes_rpki.json", "routes", map[int]float64{24: 5}},
+		{"show_bgp_ipv4_unicast_neighbors_advertised_routes.json", "advertisedRoutes", map[int]float64{24: 2}},
+	} {
+		got, err := countPrefixLengths(bytes.NewReader(readTestFixture(t, tc.fixture)), tc.key)
+		if err != nil {
+			t.Errorf("error calling countPrefixLengths with %s: %s", tc.fixture, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("countPrefixLengths(%s) = %v, want %v", tc.fixture, got, tc.expected)
+		}
+	}
+
+	if _, err := countPrefixLengths(strings.NewReader(`["10.0.0.0/24"]`), "routes"); err == nil {
+		t.Error("expected an error calling countPrefixLengths with a JSON array")
+	}
+}
+
+func TestPrefixLengthHistogram(t *testing.T) {
+	labels := []string{"default", "ipv6", "unicast", "64512", "2001:db8::2", "64513"}
+	expected := map[string]float64{
+		"frr_bgp_peer_received_prefix_length_bucket{afi=ipv6,le=16,local_as=64512,peer=2001:db8::2,peer_as=64513,safi=unicast,vrf=default}":  0,
+		"frr_bgp_peer_received_prefix_length_bucket{afi=ipv6,le=29,local_as=64512,peer=2001:db8::2,peer_as=64513,safi=unicast,vrf=default}":  0,
+		"frr_bgp_peer_received_prefix_length_bucket{afi=ipv6,le=32,local_as=64512,peer=2001:db8::2,peer_as=64513,safi=unicast,vrf=default}":  1,
+		"frr_bgp_peer_received_prefix_length_bucket{afi=ipv6,le=36,local_as=64512,peer=2001:db8::2,peer_as=64513,safi=unicast,vrf=default}":  1,
+		"frr_bgp_peer_received_prefix_length_bucket{afi=ipv6,le=40,local_as=64512,peer=2001:db8::2,peer_as=64513,safi=unicast,vrf=default}":  1,
+		"frr_bgp_peer_received_prefix_length_bucket{afi=ipv6,le=44,local_as=64512,peer=2001:db8::2,peer_as=64513,safi=unicast,vrf=default}":  1,
+		"frr_bgp_peer_received_prefix_length_bucket{afi=ipv6,le=47,local_as=64512,peer=2001:db8::2,peer_as=64513,safi=unicast,vrf=default}":  1,
+		"frr_bgp_peer_received_prefix_length_bucket{afi=ipv6,le=48,local_as=64512,peer=2001:db8::2,peer_as=64513,safi=unicast,vrf=default}":  4,
+		"frr_bgp_peer_received_prefix_length_bucket{afi=ipv6,le=64,local_as=64512,peer=2001:db8::2,peer_as=64513,safi=unicast,vrf=default}":  5,
+		"frr_bgp_peer_received_prefix_length_bucket{afi=ipv6,le=128,local_as=64512,peer=2001:db8::2,peer_as=64513,safi=unicast,vrf=default}": 5,
+		"frr_bgp_peer_received_prefix_length_sum{afi=ipv6,local_as=64512,peer=2001:db8::2,peer_as=64513,safi=unicast,vrf=default}":           240,
+		"frr_bgp_peer_received_prefix_length_count{afi=ipv6,local_as=64512,peer=2001:db8::2,peer_as=64513,safi=unicast,vrf=default}":         5,
+	}
+
+	lengths, err := countPrefixLengths(bytes.NewReader(readTestFixture(t, "show_bgp_ipv6_unicast_neighbors_routes.json")), "routes")
+	if err != nil {
+		t.Fatalf("error calling countPrefixLengths: %s", err)
+	}
+	ch := make(chan prometheus.Metric, 1)
+	ch <- prefixLengthHistogram("ipv6", lengths).metric(getBGPDesc()["prefixLengthRcvd"], labels...)
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestBGPPrefixLengthPeerSelected(t *testing.T) {
+	entries := []string{"blue/10.0.0.1", "2001:db8::2"}
+	for _, tc := range []struct {
+		vrf, peer string
+		expected  bool
+	}{
+		{"blue", "10.0.0.1", true},
+		{"default", "10.0.0.1", false},
+		{"default", "2001:db8::2", true},
+		{"red", "2001:db8::2", true},
+		{"blue", "10.0.0.2", false},
+	} {
+		if got := bgpPrefixLengthPeerSelected(entries, tc.vrf, tc.peer); got != tc.expected {
+			t.Errorf("bgpPrefixLengthPeerSelected(%v, %q, %q) = %v, want %v", entries, tc.vrf, tc.peer, got, tc.expected)
+		}
+	}
+}
+
+func TestProcessBGPUpdateGroups(t *testing.T) {
+	expected := map[string]float64{
+		"frr_bgp_update_groups{afi=ipv4,local_as=64512,safi=unicast,vrf=default}":    2.0,
+		"frr_bgp_update_subgroups{afi=ipv4,local_as=64512,safi=unicast,vrf=default}": 3.0,
+	}
+	for _, sg := range []struct {
+		group, subgroup                      string
+		peers, splits, merges, queue, adjOut float64
+	}{
+		{"1", "1", 3, 1, 2, 3, 98},
+		{"1", "4", 1, 0, 0, 0, 96},
+		{"2", "2", 1, 0, 0, 0, 10},
+	} {
+		labels := fmt.Sprintf("{afi=ipv4,local_as=64512,safi=unicast,subgroup=%s,update_group=%s,vrf=default}", sg.subgroup, sg.group)
+		expected["frr_bgp_update_subgroup_peers"+labels] = sg.peers
+		expected["frr_bgp_update_subgroup_splits_total"+labels] = sg.splits
+		expected["frr_bgp_update_subgroup_merges_total"+labels] = sg.merges
+		expected["frr_bgp_update_subgroup_packet_queue_length"+labels] = sg.queue
+		expected["frr_bgp_update_subgroup_adj_out_entries"+labels] = sg.adjOut
+	}
+
+	ch := make(chan prometheus.Metric, len(expected)*3)
+	if err := processBGPUpdateGroups(ch, readTestFixture(t, "show_bgp_ipv4_unicast_update_groups.json"), []string{"default", "ipv4", "unicast", "64512"}, getBGPDesc()); err != nil {
+		t.Errorf("error calling processBGPUpdateGroups: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
 func TestLoadPrefixFilter(t *testing.T) {
 	got, err := loadPrefixFilter(filepath.Join("testdata", "prefix_filter.txt"))
 	if err != nil {
@@ -290,3 +661,21 @@ func TestProcessBGPNexthop(t *testing.T) {
 		t.Errorf("processBGPNexthop() =\n%#v\nwant\n%#v", got, expected)
 	}
 }
+
+func TestNewBGPCollectorOverlappingCollectors(t *testing.T) {
+	defer func(afiSafis string, bgp6 bool) {
+		*bgpAFISAFIs = afiSafis
+		*collectorState[bgpSubsystem+"6"] = bgp6
+	}(*bgpAFISAFIs, *collectorState[bgpSubsystem+"6"])
+
+	*collectorState[bgpSubsystem+"6"] = true
+	*bgpAFISAFIs = "ipv4,ipv6/vpn"
+	if _, err := NewBGPCollector(nil); err == nil {
+		t.Errorf("expected an error when --collector.bgp.afi-safi overlaps the enabled bgp6 collector")
+	}
+
+	*bgpAFISAFIs = "ipv4,l2vpn"
+	if _, err := NewBGPCollector(nil); err != nil {
+		t.Errorf("unexpected error when --collector.bgp.afi-safi does not overlap an enabled collector: %v", err)
+	}
+}
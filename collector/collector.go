@@ -153,6 +153,13 @@ func newCounter(ch chan<- prometheus.Metric, descName *prometheus.Desc, metric f
 	ch <- prometheus.MustNewConstMetric(descName, prometheus.CounterValue, metric, labels...)
 }
 
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
 func cmdOutputProcessError(cmd, output string, err error) error {
 	return fmt.Errorf("cannot process output of %s: %w: command output: %s", cmd, err, output)
 }
@@ -165,13 +172,57 @@ func getVRFs() ([]string, error) {
 	return parseVRFs(output), nil
 }
 
+// parseVRFs returns the name of the default VRF followed by the VRFs of 'show vrf'.
 func parseVRFs(output []byte) []string {
-	vrfs := []string{"default"}
+	var vrfs []string
+	for _, vrf := range parseVRFDetails(output) {
+		vrfs = append(vrfs, vrf.name)
+	}
+	return vrfs
+}
+
+// zebraVRF is a VRF of 'show vrf'. The ID and table are empty when zebra does not report them.
+type zebraVRF struct {
+	name, id, table string
+	active          bool
+}
+
+// parseVRFDetails returns the default VRF followed by the VRFs of 'show vrf', which lists every VRF other than
+// the default VRF, for example:
+//
+//	vrf red id 10 table 1001 (configured)
+//	vrf green id 11 netns green
+//	vrf blue inactive (configured)
+//
+// The default VRF has the ID of VRF_DEFAULT and the main kernel table, unless 'show vrf' reports otherwise.
+func parseVRFDetails(output []byte) []zebraVRF {
+	vrfs := []zebraVRF{{name: "default", id: "0", table: "254", active: true}}
 	for _, line := range strings.Split(string(output), "\n") {
 		fields := strings.Fields(line)
-		if len(fields) >= 2 && fields[0] == "vrf" {
-			vrfs = append(vrfs, fields[1])
+		if len(fields) < 2 || fields[0] != "vrf" {
+			continue
+		}
+
+		vrf := zebraVRF{name: fields[1], active: true}
+		for i := 2; i < len(fields); i++ {
+			switch fields[i] {
+			case "inactive":
+				vrf.active = false
+			case "id":
+				if i+1 < len(fields) {
+					vrf.id = fields[i+1]
+				}
+			case "table":
+				if i+1 < len(fields) {
+					vrf.table = fields[i+1]
+				}
+			}
+		}
+		if vrf.name == "default" {
+			vrfs[0] = vrf
+			continue
 		}
+		vrfs = append(vrfs, vrf)
 	}
 	return vrfs
 }
@@ -58,6 +58,19 @@ func collectMetrics(t *testing.T, ch <-chan prometheus.Metric) map[string]float6
 		// sort them so the order is deterministic: area,iface,instance,vrf
 		sort.Strings(lbls)
 
+		// expand histograms into their _bucket, _sum and _count series
+		if h := dtoM.GetHistogram(); h != nil {
+			name := re.FindStringSubmatch(m.Desc().String())[1]
+			for _, b := range h.GetBucket() {
+				bucketLbls := append([]string{fmt.Sprintf("le=%v", b.GetUpperBound())}, lbls...)
+				sort.Strings(bucketLbls)
+				got[fmt.Sprintf("%s_bucket{%s}", name, strings.Join(bucketLbls, ","))] = float64(b.GetCumulativeCount())
+			}
+			got[fmt.Sprintf("%s_sum{%s}", name, strings.Join(lbls, ","))] = h.GetSampleSum()
+			got[fmt.Sprintf("%s_count{%s}", name, strings.Join(lbls, ","))] = float64(h.GetSampleCount())
+			continue
+		}
+
 		// grab the numeric value
 		var v float64
 		if c := dtoM.GetCounter(); c != nil {
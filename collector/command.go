@@ -3,6 +3,7 @@ package collector
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
@@ -32,6 +33,13 @@ func executeBGPCommand(cmd string) ([]byte, error) {
 	return socketConn.ExecBGPCmd(cmd)
 }
 
+func executeFabricdCommand(cmd string) ([]byte, error) {
+	if *vtyshEnable {
+		return execVtyshCommand(cmd)
+	}
+	return socketConn.ExecFabricdCmd(cmd)
+}
+
 func executeOSPFMultiInstanceCommand(cmd string, instanceID int) ([]byte, error) {
 	return socketConn.ExecOSPFMultiInstanceCmd(cmd, instanceID)
 }
@@ -64,6 +72,37 @@ func executeVRRPCommand(cmd string) ([]byte, error) {
 	return socketConn.ExecVRRPCmd(cmd)
 }
 
+// executeAllDaemonsCommand executes cmd against every daemon with a Unix socket in
+// --frr.socket.dir-path, returning the output of each daemon keyed by daemon name. Output from
+// daemons that responded is returned alongside any errors. When using vtysh, vtysh sends the
+// command to every daemon itself and its combined output is returned with an empty daemon name.
+func executeAllDaemonsCommand(cmd string) (map[string][]byte, error) {
+	if *vtyshEnable {
+		output, err := execVtyshCommand(cmd)
+		if err != nil {
+			return nil, err
+		}
+		return map[string][]byte{"": output}, nil
+	}
+
+	daemons, err := socketConn.Daemons()
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := make(map[string][]byte, len(daemons))
+	var errs []error
+	for _, daemon := range daemons {
+		output, err := socketConn.ExecDaemonCmd(daemon, cmd)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("daemon %s: %w", daemon, err))
+			continue
+		}
+		outputs[daemon] = output
+	}
+	return outputs, errors.Join(errs...)
+}
+
 func execVtyshCommand(vtyshCmd string) ([]byte, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), *vtyshTimeout)
 	defer cancel()
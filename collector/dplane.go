@@ -0,0 +1,239 @@
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	dplaneSubsystem = "dplane"
+
+	dplaneProviderLine = regexp.MustCompile(`^(.+) \((\d+)\): (.+)$`)
+
+	// The update and error counters of 'show zebra dplane detailed', keyed by the line label.
+	dplaneUpdateTypes = map[string]string{
+		"Route updates":      "route",
+		"LSP updates":        "lsp",
+		"PW updates":         "pw",
+		"EVPN MAC updates":   "evpn_mac",
+		"EVPN neigh updates": "evpn_neigh",
+	}
+	dplaneErrorTypes = map[string]string{
+		"Route update errors": "route",
+		"LSP update errors":   "lsp",
+		"PW update errors":    "pw",
+		"EVPN MAC errors":     "evpn_mac",
+		"EVPN neigh errors":   "evpn_neigh",
+	}
+)
+
+func init() {
+	registerCollector(dplaneSubsystem, disabledByDefault, NewDplaneCollector)
+}
+
+type dplaneCollector struct {
+	logger       *slog.Logger
+	descriptions map[string]*prometheus.Desc
+}
+
+// NewDplaneCollector collects zebra dataplane metrics, implemented as per the Collector interface.
+func NewDplaneCollector(logger *slog.Logger) (Collector, error) {
+	return &dplaneCollector{logger: logger, descriptions: getDplaneDesc()}, nil
+}
+
+func getDplaneDesc() map[string]*prometheus.Desc {
+	typeLabels := []string{"type"}
+	providerLabels := []string{"provider", "id"}
+
+	return map[string]*prometheus.Desc{
+		"updates":    colPromDesc(dplaneSubsystem, "updates_total", "Number of updates queued to the dataplane.", typeLabels),
+		"errors":     colPromDesc(dplaneSubsystem, "update_errors_total", "Number of dataplane updates that failed.", typeLabels),
+		"otherErrs":  colPromDesc(dplaneSubsystem, "other_errors_total", "Number of other dataplane errors.", nil),
+		"queueLimit": colPromDesc(dplaneSubsystem, "queue_limit", "Maximum length of the dataplane update queue.", nil),
+		"queueDepth": colPromDesc(dplaneSubsystem, "queue_depth", "Current length of the dataplane update queue.", nil),
+		"queueMax":   colPromDesc(dplaneSubsystem, "queue_max", "Highest length the dataplane update queue has reached.", nil),
+		"yields":     colPromDesc(dplaneSubsystem, "yields_total", "Number of times dataplane update processing yielded.", nil),
+
+		"providerIn":          colPromDesc(dplaneSubsystem, "provider_in_total", "Number of updates received by the dataplane provider.", providerLabels),
+		"providerInQueue":     colPromDesc(dplaneSubsystem, "provider_in_queue_depth", "Current length of the dataplane provider's input queue.", providerLabels),
+		"providerInQueueMax":  colPromDesc(dplaneSubsystem, "provider_in_queue_max", "Highest length the dataplane provider's input queue has reached.", providerLabels),
+		"providerOut":         colPromDesc(dplaneSubsystem, "provider_out_total", "Number of updates completed by the dataplane provider.", providerLabels),
+		"providerOutQueue":    colPromDesc(dplaneSubsystem, "provider_out_queue_depth", "Current length of the dataplane provider's output queue.", providerLabels),
+		"providerOutQueueMax": colPromDesc(dplaneSubsystem, "provider_out_queue_max", "Highest length the dataplane provider's output queue has reached.", providerLabels),
+
+		"fpmBytesRead":         colPromDesc(dplaneSubsystem, "fpm_bytes_read_total", "Number of bytes read from the FPM connection.", nil),
+		"fpmBytesSent":         colPromDesc(dplaneSubsystem, "fpm_bytes_sent_total", "Number of bytes sent on the FPM connection.", nil),
+		"fpmObufBytes":         colPromDesc(dplaneSubsystem, "fpm_output_buffer_bytes", "Bytes currently held in the FPM output buffer.", nil),
+		"fpmObufPeak":          colPromDesc(dplaneSubsystem, "fpm_output_buffer_peak_bytes", "Highest number of bytes held in the FPM output buffer.", nil),
+		"fpmConnCloses":        colPromDesc(dplaneSubsystem, "fpm_connection_closes_total", "Number of times the FPM connection was closed.", nil),
+		"fpmConnErrors":        colPromDesc(dplaneSubsystem, "fpm_connection_errors_total", "Number of FPM connection errors.", nil),
+		"fpmContexts":          colPromDesc(dplaneSubsystem, "fpm_contexts_total", "Number of dataplane contexts processed by FPM.", nil),
+		"fpmContextsQueue":     colPromDesc(dplaneSubsystem, "fpm_context_queue_depth", "Current length of the FPM dataplane context queue.", nil),
+		"fpmContextsQueuePeak": colPromDesc(dplaneSubsystem, "fpm_context_queue_max", "Highest length the FPM dataplane context queue has reached.", nil),
+		"fpmBufferFullHits":    colPromDesc(dplaneSubsystem, "fpm_buffer_full_hits_total", "Number of times the FPM output buffer was full.", nil),
+	}
+}
+
+// Update implemented as per the Collector interface.
+func (c *dplaneCollector) Update(ch chan<- prometheus.Metric) error {
+	steps := []struct {
+		cmd       string
+		processor func(chan<- prometheus.Metric, []byte, map[string]*prometheus.Desc) error
+	}{
+		{cmd: "show zebra dplane detailed", processor: processDplane},
+		{cmd: "show zebra dplane providers", processor: processDplaneProviders},
+	}
+
+	for _, s := range steps {
+		output, err := executeZebraCommand(s.cmd)
+		if err != nil {
+			return err
+		}
+		if err := s.processor(ch, output, c.descriptions); err != nil {
+			return cmdOutputProcessError(s.cmd, string(output), err)
+		}
+	}
+
+	// FPM counters are only available when zebra is started with the dplane_fpm_nl module. Without it, the command
+	// is unknown, which fails in --frr.vtysh mode and returns an error message over the socket.
+	cmd := "show fpm counters json"
+	output, err := executeZebraCommand(cmd)
+	if err != nil {
+		c.logger.Debug("FPM counters not available", "err", err)
+		return nil
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(output), []byte("{")) {
+		c.logger.Debug("FPM counters not available", "output", string(output))
+		return nil
+	}
+	if err := processDplaneFPM(ch, output, c.descriptions); err != nil {
+		return cmdOutputProcessError(cmd, string(output), err)
+	}
+	return nil
+}
+
+func processDplane(ch chan<- prometheus.Metric, output []byte, dplaneDesc map[string]*prometheus.Desc) error {
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		label, rawValue, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		label = strings.TrimSpace(label)
+		value, err := strconv.ParseUint(strings.TrimSpace(rawValue), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if updateType, ok := dplaneUpdateTypes[label]; ok {
+			newCounter(ch, dplaneDesc["updates"], float64(value), updateType)
+			continue
+		}
+		if errorType, ok := dplaneErrorTypes[label]; ok {
+			newCounter(ch, dplaneDesc["errors"], float64(value), errorType)
+			continue
+		}
+
+		switch label {
+		case "Other errors":
+			newCounter(ch, dplaneDesc["otherErrs"], float64(value))
+		case "Route update queue limit":
+			newGauge(ch, dplaneDesc["queueLimit"], float64(value))
+		case "Route update queue depth":
+			newGauge(ch, dplaneDesc["queueDepth"], float64(value))
+		case "Route update queue max":
+			newGauge(ch, dplaneDesc["queueMax"], float64(value))
+		case "Dplane update yields":
+			newCounter(ch, dplaneDesc["yields"], float64(value))
+		}
+	}
+	return scanner.Err()
+}
+
+// processDplaneProviders parses the output of 'show zebra dplane providers', for example:
+//
+//	Zebra dataplane providers:
+//	Kernel (1): in: 6, q: 0, q_max: 3, out: 6, q: 0, q_max: 3
+//
+// The first queue statistics belong to the provider's input queue and the second to its output
+// queue. zebra does not print an error counter per provider: the errors of the kernel provider are the update
+// errors of 'show zebra dplane detailed', and those of the FPM provider are in its FPM counters.
+func processDplaneProviders(ch chan<- prometheus.Metric, output []byte, dplaneDesc map[string]*prometheus.Desc) error {
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		match := dplaneProviderLine.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		labels := []string{match[1], match[2]}
+
+		out := false
+		for _, stat := range strings.Split(match[3], ",") {
+			key, rawValue, ok := strings.Cut(stat, ":")
+			if !ok {
+				continue
+			}
+			value, err := strconv.ParseUint(strings.TrimSpace(rawValue), 10, 64)
+			if err != nil {
+				continue
+			}
+
+			key = strings.TrimSpace(key)
+			switch {
+			case key == "in":
+				newCounter(ch, dplaneDesc["providerIn"], float64(value), labels...)
+			case key == "out":
+				out = true
+				newCounter(ch, dplaneDesc["providerOut"], float64(value), labels...)
+			case key == "q" && !out:
+				newGauge(ch, dplaneDesc["providerInQueue"], float64(value), labels...)
+			case key == "q_max" && !out:
+				newGauge(ch, dplaneDesc["providerInQueueMax"], float64(value), labels...)
+			case key == "q":
+				newGauge(ch, dplaneDesc["providerOutQueue"], float64(value), labels...)
+			case key == "q_max":
+				newGauge(ch, dplaneDesc["providerOutQueueMax"], float64(value), labels...)
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+func processDplaneFPM(ch chan<- prometheus.Metric, jsonFPM []byte, dplaneDesc map[string]*prometheus.Desc) error {
+	var counters fpmCounters
+	if err := json.Unmarshal(jsonFPM, &counters); err != nil {
+		return err
+	}
+
+	newCounter(ch, dplaneDesc["fpmBytesRead"], float64(counters.BytesRead))
+	newCounter(ch, dplaneDesc["fpmBytesSent"], float64(counters.BytesSent))
+	newGauge(ch, dplaneDesc["fpmObufBytes"], float64(counters.ObufBytes))
+	newGauge(ch, dplaneDesc["fpmObufPeak"], float64(counters.ObufPeak))
+	newCounter(ch, dplaneDesc["fpmConnCloses"], float64(counters.ConnectionCloses))
+	newCounter(ch, dplaneDesc["fpmConnErrors"], float64(counters.ConnectionErrors))
+	newCounter(ch, dplaneDesc["fpmContexts"], float64(counters.DplaneContexts))
+	newGauge(ch, dplaneDesc["fpmContextsQueue"], float64(counters.DplaneContextsQueue))
+	newGauge(ch, dplaneDesc["fpmContextsQueuePeak"], float64(counters.DplaneContextsQueuePeak))
+	newCounter(ch, dplaneDesc["fpmBufferFullHits"], float64(counters.BufferFullHits))
+	return nil
+}
+
+type fpmCounters struct {
+	BytesRead               uint64 `json:"bytes-read"`
+	BytesSent               uint64 `json:"bytes-sent"`
+	ObufBytes               uint64 `json:"obuf-bytes"`
+	ObufPeak                uint64 `json:"obuf-bytes-peak"`
+	ConnectionCloses        uint64 `json:"connection-closes"`
+	ConnectionErrors        uint64 `json:"connection-errors"`
+	DplaneContexts          uint64 `json:"data-plane-contexts"`
+	DplaneContextsQueue     uint64 `json:"data-plane-contexts-queue"`
+	DplaneContextsQueuePeak uint64 `json:"data-plane-contexts-queue-peak"`
+	BufferFullHits          uint64 `json:"buffer-full-hits"`
+}
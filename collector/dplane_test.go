@@ -0,0 +1,83 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestProcessDplane(t *testing.T) {
+	expected := map[string]float64{
+		"frr_dplane_updates_total{type=route}":            15320,
+		"frr_dplane_update_errors_total{type=route}":      3,
+		"frr_dplane_updates_total{type=lsp}":              120,
+		"frr_dplane_update_errors_total{type=lsp}":        0,
+		"frr_dplane_updates_total{type=pw}":               0,
+		"frr_dplane_update_errors_total{type=pw}":         0,
+		"frr_dplane_updates_total{type=evpn_mac}":         30,
+		"frr_dplane_update_errors_total{type=evpn_mac}":   0,
+		"frr_dplane_updates_total{type=evpn_neigh}":       25,
+		"frr_dplane_update_errors_total{type=evpn_neigh}": 0,
+		"frr_dplane_other_errors_total{}":                 1,
+		"frr_dplane_queue_limit{}":                        200,
+		"frr_dplane_queue_depth{}":                        12,
+		"frr_dplane_queue_max{}":                          180,
+		"frr_dplane_yields_total{}":                       42,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processDplane(ch, readTestFixture(t, "show_zebra_dplane_detailed.txt"), getDplaneDesc()); err != nil {
+		t.Errorf("error calling processDplane: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestProcessDplaneProviders(t *testing.T) {
+	expected := map[string]float64{
+		"frr_dplane_provider_in_total{id=1,provider=Kernel}":               15320,
+		"frr_dplane_provider_in_queue_depth{id=1,provider=Kernel}":         2,
+		"frr_dplane_provider_in_queue_max{id=1,provider=Kernel}":           150,
+		"frr_dplane_provider_out_total{id=1,provider=Kernel}":              15318,
+		"frr_dplane_provider_out_queue_depth{id=1,provider=Kernel}":        0,
+		"frr_dplane_provider_out_queue_max{id=1,provider=Kernel}":          20,
+		"frr_dplane_provider_in_total{id=2,provider=dplane_fpm_nl}":        15318,
+		"frr_dplane_provider_in_queue_depth{id=2,provider=dplane_fpm_nl}":  0,
+		"frr_dplane_provider_in_queue_max{id=2,provider=dplane_fpm_nl}":    40,
+		"frr_dplane_provider_out_total{id=2,provider=dplane_fpm_nl}":       15318,
+		"frr_dplane_provider_out_queue_depth{id=2,provider=dplane_fpm_nl}": 0,
+		"frr_dplane_provider_out_queue_max{id=2,provider=dplane_fpm_nl}":   3,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processDplaneProviders(ch, readTestFixture(t, "show_zebra_dplane_providers.txt"), getDplaneDesc()); err != nil {
+		t.Errorf("error calling processDplaneProviders: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestProcessDplaneFPM(t *testing.T) {
+	expected := map[string]float64{
+		"frr_dplane_fpm_bytes_read_total{}":         0,
+		"frr_dplane_fpm_bytes_sent_total{}":         1843200,
+		"frr_dplane_fpm_output_buffer_bytes{}":      0,
+		"frr_dplane_fpm_output_buffer_peak_bytes{}": 65536,
+		"frr_dplane_fpm_connection_closes_total{}":  2,
+		"frr_dplane_fpm_connection_errors_total{}":  1,
+		"frr_dplane_fpm_contexts_total{}":           15318,
+		"frr_dplane_fpm_context_queue_depth{}":      0,
+		"frr_dplane_fpm_context_queue_max{}":        96,
+		"frr_dplane_fpm_buffer_full_hits_total{}":   4,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processDplaneFPM(ch, readTestFixture(t, "show_fpm_counters.json"), getDplaneDesc()); err != nil {
+		t.Errorf("error calling processDplaneFPM: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
@@ -0,0 +1,336 @@
+package collector
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	evpnSubsystem   = "evpn"
+	evpnMultihoming = kingpin.Flag("collector.evpn.multihoming", "Enable EVPN multihoming Ethernet Segment metrics (default: disabled).").Default("False").Bool()
+)
+
+func init() {
+	registerCollector(evpnSubsystem, disabledByDefault, NewEVPNCollector)
+}
+
+type evpnCollector struct {
+	logger       *slog.Logger
+	descriptions map[string]*prometheus.Desc
+}
+
+// NewEVPNCollector collects zebra EVPN metrics, implemented as per the Collector interface.
+func NewEVPNCollector(logger *slog.Logger) (Collector, error) {
+	return &evpnCollector{logger: logger, descriptions: getEVPNDesc()}, nil
+}
+
+func getEVPNDesc() map[string]*prometheus.Desc {
+	vniLabels := []string{"vni"}
+	typeLabels := []string{"vni", "type"}
+	l3vniLabels := []string{"vni", "vrf"}
+	esLabels := []string{"esi"}
+	esVtepLabels := []string{"esi", "vtep"}
+
+	return map[string]*prometheus.Desc{
+		"macs":               colPromDesc(evpnSubsystem, "macs", "Number of MAC addresses in the VNI by type (local, remote or auto).", typeLabels),
+		"macsSticky":         colPromDesc(evpnSubsystem, "macs_sticky", "Number of sticky MAC addresses in the VNI.", vniLabels),
+		"macsStatic":         colPromDesc(evpnSubsystem, "macs_static", "Number of MAC addresses in the VNI zebra installs as static, i.e. MACs synced from or proxied for an Ethernet Segment peer, or with synced neighbors.", vniLabels),
+		"macsGateway":        colPromDesc(evpnSubsystem, "macs_gateway", "Number of gateway MAC addresses in the VNI, i.e. SVI and default gateway MACs.", vniLabels),
+		"macSequenceSum":     colPromDesc(evpnSubsystem, "mac_mobility_sequence_sum", "Sum of the highest MAC mobility sequence number (RFC 7432) of each MAC address currently in the VNI. This is not a count of MAC moves, it decreases when MAC addresses are removed.", vniLabels),
+		"macsDuplicate":      colPromDesc(evpnSubsystem, "macs_duplicate", "Number of MAC addresses in the VNI detected as duplicate by duplicate address detection.", vniLabels),
+		"neighbors":          colPromDesc(evpnSubsystem, "neighbors", "Number of ARP/ND entries in the VNI by type (local or remote).", typeLabels),
+		"neighborsDuplicate": colPromDesc(evpnSubsystem, "neighbors_duplicate", "Number of ARP/ND entries in the VNI detected as duplicate by duplicate address detection.", vniLabels),
+
+		"remoteVtep":  colPromDesc(evpnSubsystem, "remote_vtep", "Remote VTEP in the flood list of the L2VNI, along with its flood type (HER = head-end replication, PIM-SM).", []string{"vni", "vtep", "flood"}),
+		"l3vniInfo":   colPromDesc(evpnSubsystem, "l3vni_info", "L3VNI along with its tenant VRF, VXLAN interface and router MAC.", []string{"vni", "vrf", "vxlan_if", "router_mac"}),
+		"l3vniUp":     colPromDesc(evpnSubsystem, "l3vni_up", "Operational state of the L3VNI (1 = up, 0 = down).", l3vniLabels),
+		"l3vniL2vnis": colPromDesc(evpnSubsystem, "l3vni_l2vnis", "Number of L2VNIs associated with the L3VNI.", l3vniLabels),
+
+		"esInfo":                 colPromDesc(evpnSubsystem, "es_info", "Ethernet Segment and its local access interface, if any.", []string{"esi", "iface"}),
+		"esOperUp":               colPromDesc(evpnSubsystem, "es_oper_up", "Operational state of the local Ethernet Segment (1 = up, 0 = down).", esLabels),
+		"esDF":                   colPromDesc(evpnSubsystem, "es_designated_forwarder", "Whether this VTEP is the designated forwarder of the local Ethernet Segment (1 = DF, 0 = non-DF).", esLabels),
+		"esDFPreference":         colPromDesc(evpnSubsystem, "es_df_preference", "Designated forwarder election preference of this VTEP for the local Ethernet Segment.", esLabels),
+		"esPeerVteps":            colPromDesc(evpnSubsystem, "es_peer_vteps", "Number of peer VTEPs attached to the Ethernet Segment.", esLabels),
+		"esPeerDFPreference":     colPromDesc(evpnSubsystem, "es_peer_df_preference", "Designated forwarder election preference advertised by the peer VTEP for the Ethernet Segment.", esVtepLabels),
+		"esEVIs":                 colPromDesc(evpnSubsystem, "es_evis", "Number of EVIs (VNIs) the Ethernet Segment is attached to.", esLabels),
+		"esMACs":                 colPromDesc(evpnSubsystem, "es_macs", "Number of MAC addresses learned on the Ethernet Segment.", esLabels),
+		"esBGPRemoteEVIs":        colPromDesc(evpnSubsystem, "es_bgp_remote_evis", "Number of EVIs of the Ethernet Segment learned from remote VTEPs by BGP.", esLabels),
+		"esBGPActiveVteps":       colPromDesc(evpnSubsystem, "es_bgp_active_peer_vteps", "Number of peer VTEPs BGP considers active on the Ethernet Segment.", esLabels),
+		"esBGPInconsistentVteps": colPromDesc(evpnSubsystem, "es_bgp_inconsistent_vni_vteps", "Number of VNI-VTEP pairs of the Ethernet Segment that are inconsistent.", esLabels),
+		"esBGPMacipPaths":        colPromDesc(evpnSubsystem, "es_bgp_macip_paths", "Number of MAC-IP paths that reference the Ethernet Segment.", esLabels),
+	}
+}
+
+// Update implemented as per the Collector interface.
+func (c *evpnCollector) Update(ch chan<- prometheus.Metric) error {
+	type step struct {
+		cmd       string
+		exec      func(string) ([]byte, error)
+		processor func(chan<- prometheus.Metric, []byte, map[string]*prometheus.Desc) error
+	}
+	steps := []step{
+		{cmd: "show evpn mac vni all detail json", exec: executeZebraCommand, processor: processEVPNMACs},
+		{cmd: "show evpn arp-cache vni all json", exec: executeZebraCommand, processor: processEVPNNeighbors},
+		{cmd: "show evpn vni detail json", exec: executeZebraCommand, processor: processEVPNVNIs},
+	}
+	if *evpnMultihoming {
+		steps = append(steps,
+			step{cmd: "show evpn es detail json", exec: executeZebraCommand, processor: processEVPNES},
+			step{cmd: "show bgp l2vpn evpn es detail json", exec: executeBGPCommand, processor: processBGPEVPNES},
+		)
+	}
+
+	for _, s := range steps {
+		output, err := s.exec(s.cmd)
+		if err != nil {
+			return err
+		}
+		// zebra returns no output at all when EVPN is not enabled.
+		if len(output) == 0 {
+			continue
+		}
+		if err := s.processor(ch, output, c.descriptions); err != nil {
+			return cmdOutputProcessError(s.cmd, string(output), err)
+		}
+	}
+	return nil
+}
+
+func processEVPNMACs(ch chan<- prometheus.Metric, jsonMACs []byte, evpnDesc map[string]*prometheus.Desc) error {
+	var vnis map[string]evpnMACVNI
+	if err := json.Unmarshal(jsonMACs, &vnis); err != nil {
+		return err
+	}
+
+	for vni, vniData := range vnis {
+		types := make(map[string]float64)
+		var sticky, static, gateway, sequenceSum, duplicate float64
+		for _, mac := range vniData.MACs {
+			types[mac.Type]++
+			if mac.Sticky {
+				sticky++
+			}
+			// As per zebra_evpn_mac_is_static.
+			if mac.PeerProxy || mac.PeerActive || mac.SyncNeighCount > 0 {
+				static++
+			}
+			if mac.SVI || mac.DefaultGateway {
+				gateway++
+			}
+			sequenceSum += float64(max(mac.LocalSequence, mac.RemoteSequence))
+			if mac.IsDuplicate {
+				duplicate++
+			}
+		}
+
+		for macType, count := range types {
+			newGauge(ch, evpnDesc["macs"], count, vni, macType)
+		}
+		newGauge(ch, evpnDesc["macsSticky"], sticky, vni)
+		newGauge(ch, evpnDesc["macsStatic"], static, vni)
+		newGauge(ch, evpnDesc["macsGateway"], gateway, vni)
+		newGauge(ch, evpnDesc["macSequenceSum"], sequenceSum, vni)
+		newGauge(ch, evpnDesc["macsDuplicate"], duplicate, vni)
+	}
+	return nil
+}
+
+// processEVPNNeighbors processes the output of 'show evpn arp-cache vni all json', where each VNI
+// object holds the number of entries under "numArpNd" alongside one object per IP address.
+func processEVPNNeighbors(ch chan<- prometheus.Metric, jsonNeighbors []byte, evpnDesc map[string]*prometheus.Desc) error {
+	var vnis map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(jsonNeighbors, &vnis); err != nil {
+		return err
+	}
+
+	for vni, entries := range vnis {
+		types := make(map[string]float64)
+		duplicate := 0.0
+		for key, raw := range entries {
+			if key == "numArpNd" {
+				continue
+			}
+			var neigh evpnNeighbor
+			if err := json.Unmarshal(raw, &neigh); err != nil {
+				return err
+			}
+			types[neigh.Type]++
+			if neigh.IsDuplicate {
+				duplicate++
+			}
+		}
+
+		for neighType, count := range types {
+			newGauge(ch, evpnDesc["neighbors"], count, vni, neighType)
+		}
+		newGauge(ch, evpnDesc["neighborsDuplicate"], duplicate, vni)
+	}
+	return nil
+}
+
+// processEVPNVNIs processes the output of 'show evpn vni detail json', which is an array of L2VNI
+// and L3VNI objects, or an object keyed by VNI in some FRR releases.
+func processEVPNVNIs(ch chan<- prometheus.Metric, jsonVNIs []byte, evpnDesc map[string]*prometheus.Desc) error {
+	var vnis []evpnVNIDetail
+	if err := json.Unmarshal(jsonVNIs, &vnis); err != nil {
+		var vniMap map[string]evpnVNIDetail
+		if err := json.Unmarshal(jsonVNIs, &vniMap); err != nil {
+			return err
+		}
+		for _, vni := range vniMap {
+			vnis = append(vnis, vni)
+		}
+	}
+
+	for _, vni := range vnis {
+		vniID := strconv.FormatUint(uint64(vni.VNI), 10)
+		switch vni.Type {
+		case "L2":
+			vteps, err := vni.remoteVteps()
+			if err != nil {
+				return err
+			}
+			for _, vtep := range vteps {
+				newGauge(ch, evpnDesc["remoteVtep"], 1, vniID, vtep.IP, vtep.Flood)
+			}
+		case "L3":
+			newGauge(ch, evpnDesc["l3vniInfo"], 1, vniID, vni.VRF, vni.VxlanIntf, vni.RouterMAC)
+			newGauge(ch, evpnDesc["l3vniUp"], boolToFloat(strings.EqualFold(vni.State, "up")), vniID, vni.VRF)
+			newGauge(ch, evpnDesc["l3vniL2vnis"], float64(len(vni.L2VNIs)), vniID, vni.VRF)
+		}
+	}
+	return nil
+}
+
+func processEVPNES(ch chan<- prometheus.Metric, jsonES []byte, evpnDesc map[string]*prometheus.Desc) error {
+	var segments []evpnES
+	if err := json.Unmarshal(jsonES, &segments); err != nil {
+		return err
+	}
+
+	for _, es := range segments {
+		newGauge(ch, evpnDesc["esInfo"], 1, es.ESI, es.AccessPort)
+		if slices.Contains(es.Flags, "local") {
+			newGauge(ch, evpnDesc["esOperUp"], boolToFloat(slices.Contains(es.Flags, "operUp")), es.ESI)
+			newGauge(ch, evpnDesc["esDF"], boolToFloat(!slices.Contains(es.Flags, "nonDF")), es.ESI)
+			newGauge(ch, evpnDesc["esDFPreference"], float64(es.DFPreference), es.ESI)
+		}
+		newGauge(ch, evpnDesc["esPeerVteps"], float64(len(es.Vteps)), es.ESI)
+		for _, vtep := range es.Vteps {
+			newGauge(ch, evpnDesc["esPeerDFPreference"], float64(vtep.DFPreference), es.ESI, vtep.Vtep)
+		}
+		newGauge(ch, evpnDesc["esEVIs"], float64(es.VNICount), es.ESI)
+		newGauge(ch, evpnDesc["esMACs"], float64(es.MACCount), es.ESI)
+	}
+	return nil
+}
+
+func processBGPEVPNES(ch chan<- prometheus.Metric, jsonES []byte, evpnDesc map[string]*prometheus.Desc) error {
+	var segments []bgpEVPNES
+	if err := json.Unmarshal(jsonES, &segments); err != nil {
+		return err
+	}
+
+	for _, es := range segments {
+		activeVteps := 0.0
+		for _, vtep := range es.Vteps {
+			if slices.Contains(vtep.Flags, "esActive") {
+				activeVteps++
+			}
+		}
+		newGauge(ch, evpnDesc["esBGPRemoteEVIs"], float64(es.RemoteVNICount), es.ESI)
+		newGauge(ch, evpnDesc["esBGPActiveVteps"], activeVteps, es.ESI)
+		newGauge(ch, evpnDesc["esBGPInconsistentVteps"], float64(es.InconsistentVNIVtepCount), es.ESI)
+		newGauge(ch, evpnDesc["esBGPMacipPaths"], float64(es.MacipPathCount), es.ESI)
+	}
+	return nil
+}
+
+type evpnVNIDetail struct {
+	VNI       uint32 `json:"vni"`
+	Type      string `json:"type"`
+	VRF       string `json:"vrf"`
+	VxlanIntf string `json:"vxlanIntf"`
+	State     string `json:"state"`
+	RouterMAC string `json:"routerMac"`
+	L2VNIs    []any  `json:"l2Vnis"`
+	// Depending on the FRR release, the remote VTEP list is either under remoteVteps or replaces
+	// the count under numRemoteVteps.
+	RemoteVteps    json.RawMessage `json:"remoteVteps"`
+	NumRemoteVteps json.RawMessage `json:"numRemoteVteps"`
+}
+
+type evpnRemoteVtep struct {
+	IP    string `json:"ip"`
+	Flood string `json:"flood"`
+}
+
+func (v evpnVNIDetail) remoteVteps() ([]evpnRemoteVtep, error) {
+	var vteps []evpnRemoteVtep
+	for _, raw := range []json.RawMessage{v.RemoteVteps, v.NumRemoteVteps} {
+		if !bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+			continue
+		}
+		if err := json.Unmarshal(raw, &vteps); err != nil {
+			return nil, err
+		}
+		break
+	}
+	return vteps, nil
+}
+
+type evpnMACVNI struct {
+	MACs map[string]evpnMAC `json:"macs"`
+}
+
+type evpnMAC struct {
+	Type           string `json:"type"`
+	Sticky         bool   `json:"stickyMac"`
+	SVI            bool   `json:"sviMac"`
+	DefaultGateway bool   `json:"defaultGateway"`
+	PeerProxy      bool   `json:"peerProxy"`
+	PeerActive     bool   `json:"peerActive"`
+	SyncNeighCount uint32 `json:"syncNeighCount"`
+	LocalSequence  uint32 `json:"localSequence"`
+	RemoteSequence uint32 `json:"remoteSequence"`
+	IsDuplicate    bool   `json:"isDuplicate"`
+}
+
+type evpnNeighbor struct {
+	Type        string `json:"type"`
+	IsDuplicate bool   `json:"isDuplicate"`
+}
+
+type evpnES struct {
+	ESI          string     `json:"esi"`
+	AccessPort   string     `json:"accessPort"`
+	Flags        []string   `json:"flags"`
+	VNICount     uint32     `json:"vniCount"`
+	MACCount     uint32     `json:"macCount"`
+	DFPreference uint32     `json:"dfPreference"`
+	Vteps        []evpnVtep `json:"vteps"`
+}
+
+type evpnVtep struct {
+	Vtep         string `json:"vtep"`
+	DFPreference uint32 `json:"dfPreference"`
+}
+
+type bgpEVPNES struct {
+	ESI                      string        `json:"esi"`
+	RemoteVNICount           uint32        `json:"remoteVniCount"`
+	InconsistentVNIVtepCount uint32        `json:"inconsistentVniVtepCount"`
+	MacipPathCount           uint32        `json:"macipPathCount"`
+	Vteps                    []bgpEVPNVtep `json:"vteps"`
+}
+
+type bgpEVPNVtep struct {
+	Flags []string `json:"flags"`
+}
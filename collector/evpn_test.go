@@ -0,0 +1,127 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestProcessEVPNMACs(t *testing.T) {
+	expected := map[string]float64{
+		"frr_evpn_macs{type=local,vni=1000}":           4,
+		"frr_evpn_macs{type=remote,vni=1000}":          2,
+		"frr_evpn_macs_sticky{vni=1000}":               1,
+		"frr_evpn_macs_static{vni=1000}":               2,
+		"frr_evpn_macs_gateway{vni=1000}":              1,
+		"frr_evpn_mac_mobility_sequence_sum{vni=1000}": 5,
+		"frr_evpn_macs_duplicate{vni=1000}":            1,
+		"frr_evpn_macs_sticky{vni=2000}":               0,
+		"frr_evpn_macs_static{vni=2000}":               0,
+		"frr_evpn_macs_gateway{vni=2000}":              0,
+		"frr_evpn_mac_mobility_sequence_sum{vni=2000}": 0,
+		"frr_evpn_macs_duplicate{vni=2000}":            0,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processEVPNMACs(ch, readTestFixture(t, "show_evpn_mac_vni_all_detail.json"), getEVPNDesc()); err != nil {
+		t.Errorf("error calling processEVPNMACs: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestProcessEVPNNeighbors(t *testing.T) {
+	expected := map[string]float64{
+		"frr_evpn_neighbors{type=local,vni=1000}":  1,
+		"frr_evpn_neighbors{type=remote,vni=1000}": 2,
+		"frr_evpn_neighbors_duplicate{vni=1000}":   1,
+		"frr_evpn_neighbors_duplicate{vni=2000}":   0,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processEVPNNeighbors(ch, readTestFixture(t, "show_evpn_arp_cache_vni_all.json"), getEVPNDesc()); err != nil {
+		t.Errorf("error calling processEVPNNeighbors: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestProcessEVPNES(t *testing.T) {
+	expected := map[string]float64{
+		"frr_evpn_es_info{esi=03:44:38:39:ff:ff:01:00:00:01,iface=hostbond1}":             1,
+		"frr_evpn_es_oper_up{esi=03:44:38:39:ff:ff:01:00:00:01}":                          1,
+		"frr_evpn_es_designated_forwarder{esi=03:44:38:39:ff:ff:01:00:00:01}":             1,
+		"frr_evpn_es_df_preference{esi=03:44:38:39:ff:ff:01:00:00:01}":                    50000,
+		"frr_evpn_es_peer_vteps{esi=03:44:38:39:ff:ff:01:00:00:01}":                       1,
+		"frr_evpn_es_peer_df_preference{esi=03:44:38:39:ff:ff:01:00:00:01,vtep=10.0.0.2}": 32767,
+		"frr_evpn_es_evis{esi=03:44:38:39:ff:ff:01:00:00:01}":                             10,
+		"frr_evpn_es_macs{esi=03:44:38:39:ff:ff:01:00:00:01}":                             4,
+		"frr_evpn_es_info{esi=03:44:38:39:ff:ff:01:00:00:02,iface=hostbond2}":             1,
+		"frr_evpn_es_oper_up{esi=03:44:38:39:ff:ff:01:00:00:02}":                          0,
+		"frr_evpn_es_designated_forwarder{esi=03:44:38:39:ff:ff:01:00:00:02}":             0,
+		"frr_evpn_es_df_preference{esi=03:44:38:39:ff:ff:01:00:00:02}":                    32767,
+		"frr_evpn_es_peer_vteps{esi=03:44:38:39:ff:ff:01:00:00:02}":                       2,
+		"frr_evpn_es_peer_df_preference{esi=03:44:38:39:ff:ff:01:00:00:02,vtep=10.0.0.2}": 50000,
+		"frr_evpn_es_peer_df_preference{esi=03:44:38:39:ff:ff:01:00:00:02,vtep=10.0.0.3}": 100,
+		"frr_evpn_es_evis{esi=03:44:38:39:ff:ff:01:00:00:02}":                             10,
+		"frr_evpn_es_macs{esi=03:44:38:39:ff:ff:01:00:00:02}":                             0,
+		"frr_evpn_es_info{esi=03:44:38:39:ff:ff:02:00:00:01,iface=}":                      1,
+		"frr_evpn_es_peer_vteps{esi=03:44:38:39:ff:ff:02:00:00:01}":                       1,
+		"frr_evpn_es_peer_df_preference{esi=03:44:38:39:ff:ff:02:00:00:01,vtep=10.0.0.4}": 32767,
+		"frr_evpn_es_evis{esi=03:44:38:39:ff:ff:02:00:00:01}":                             0,
+		"frr_evpn_es_macs{esi=03:44:38:39:ff:ff:02:00:00:01}":                             2,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processEVPNES(ch, readTestFixture(t, "show_evpn_es_detail.json"), getEVPNDesc()); err != nil {
+		t.Errorf("error calling processEVPNES: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestProcessBGPEVPNES(t *testing.T) {
+	expected := map[string]float64{
+		"frr_evpn_es_bgp_remote_evis{esi=03:44:38:39:ff:ff:01:00:00:01}":            10,
+		"frr_evpn_es_bgp_active_peer_vteps{esi=03:44:38:39:ff:ff:01:00:00:01}":      1,
+		"frr_evpn_es_bgp_inconsistent_vni_vteps{esi=03:44:38:39:ff:ff:01:00:00:01}": 0,
+		"frr_evpn_es_bgp_macip_paths{esi=03:44:38:39:ff:ff:01:00:00:01}":            4,
+		"frr_evpn_es_bgp_remote_evis{esi=03:44:38:39:ff:ff:01:00:00:02}":            8,
+		"frr_evpn_es_bgp_active_peer_vteps{esi=03:44:38:39:ff:ff:01:00:00:02}":      1,
+		"frr_evpn_es_bgp_inconsistent_vni_vteps{esi=03:44:38:39:ff:ff:01:00:00:02}": 2,
+		"frr_evpn_es_bgp_macip_paths{esi=03:44:38:39:ff:ff:01:00:00:02}":            0,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processBGPEVPNES(ch, readTestFixture(t, "show_bgp_l2vpn_evpn_es_detail.json"), getEVPNDesc()); err != nil {
+		t.Errorf("error calling processBGPEVPNES: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestProcessEVPNVNIs(t *testing.T) {
+	expected := map[string]float64{
+		"frr_evpn_remote_vtep{flood=HER,vni=1000,vtep=10.0.0.2}":                               1,
+		"frr_evpn_remote_vtep{flood=HER,vni=1000,vtep=10.0.0.3}":                               1,
+		"frr_evpn_remote_vtep{flood=PIM-SM,vni=2000,vtep=10.0.0.4}":                            1,
+		"frr_evpn_l3vni_info{router_mac=44:38:39:ff:ff:01,vni=4001,vrf=red,vxlan_if=vni4001}":  1,
+		"frr_evpn_l3vni_up{vni=4001,vrf=red}":                                                  1,
+		"frr_evpn_l3vni_l2vnis{vni=4001,vrf=red}":                                              1,
+		"frr_evpn_l3vni_info{router_mac=44:38:39:ff:ff:02,vni=4002,vrf=blue,vxlan_if=vni4002}": 1,
+		"frr_evpn_l3vni_up{vni=4002,vrf=blue}":                                                 0,
+		"frr_evpn_l3vni_l2vnis{vni=4002,vrf=blue}":                                             0,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processEVPNVNIs(ch, readTestFixture(t, "show_evpn_vni_detail.json"), getEVPNDesc()); err != nil {
+		t.Errorf("error calling processEVPNVNIs: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
@@ -0,0 +1,170 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	interfaceSubsystem = "interface"
+	interfaceInclude   = kingpin.Flag("collector.interface.include", "Regex of interface names to collect metrics for (default: all interfaces).").Default("").String()
+	interfaceExclude   = kingpin.Flag("collector.interface.exclude", "Regex of interface names to exclude from collection, applied after --collector.interface.include (default: none).").Default("").String()
+)
+
+func init() {
+	registerCollector(interfaceSubsystem, disabledByDefault, NewInterfaceCollector)
+}
+
+type interfaceCollector struct {
+	logger       *slog.Logger
+	descriptions map[string]*prometheus.Desc
+	include      *regexp.Regexp
+	exclude      *regexp.Regexp
+}
+
+// NewInterfaceCollector collects zebra interface metrics, implemented as per the Collector interface.
+func NewInterfaceCollector(logger *slog.Logger) (Collector, error) {
+	c := &interfaceCollector{logger: logger, descriptions: getInterfaceDesc()}
+
+	var err error
+	if *interfaceInclude != "" {
+		if c.include, err = regexp.Compile(*interfaceInclude); err != nil {
+			return nil, fmt.Errorf("unable to parse --collector.interface.include: %w", err)
+		}
+	}
+	if *interfaceExclude != "" {
+		if c.exclude, err = regexp.Compile(*interfaceExclude); err != nil {
+			return nil, fmt.Errorf("unable to parse --collector.interface.exclude: %w", err)
+		}
+	}
+	return c, nil
+}
+
+func getInterfaceDesc() map[string]*prometheus.Desc {
+	labels := []string{"iface", "vrf"}
+	reasonLabels := append(labels, "reason")
+
+	return map[string]*prometheus.Desc{
+		"adminUp":         colPromDesc(interfaceSubsystem, "admin_up", "Administrative status of the interface (1 = up, 0 = down).", labels),
+		"operUp":          colPromDesc(interfaceSubsystem, "oper_up", "Operational status of the interface (1 = up, 0 = down). Only reported when link detection is enabled.", labels),
+		"linkDetection":   colPromDesc(interfaceSubsystem, "link_detection", "Whether link detection is enabled on the interface (1 = enabled, 0 = disabled).", labels),
+		"protodown":       colPromDesc(interfaceSubsystem, "protodown", "Whether the interface is protocol down (1 = protodown, 0 = not protodown).", labels),
+		"protodownReason": colPromDesc(interfaceSubsystem, "protodown_reason", "Reason the interface is protocol down.", reasonLabels),
+		"mtu":             colPromDesc(interfaceSubsystem, "mtu_bytes", "MTU of the interface.", labels),
+		"speed":           colPromDesc(interfaceSubsystem, "speed_bytes", "Speed of the interface in bytes per second.", labels),
+		"linkUps":         colPromDesc(interfaceSubsystem, "link_ups_total", "Number of times zebra has seen the link come up.", labels),
+		"linkDowns":       colPromDesc(interfaceSubsystem, "link_downs_total", "Number of times zebra has seen the link go down.", labels),
+		"rxPackets":       colPromDesc(interfaceSubsystem, "receive_packets_total", "Number of packets received.", labels),
+		"rxBytes":         colPromDesc(interfaceSubsystem, "receive_bytes_total", "Number of bytes received.", labels),
+		"rxDropped":       colPromDesc(interfaceSubsystem, "receive_dropped_total", "Number of received packets dropped.", labels),
+		"rxErrors":        colPromDesc(interfaceSubsystem, "receive_errors_total", "Number of receive errors.", labels),
+		"txPackets":       colPromDesc(interfaceSubsystem, "transmit_packets_total", "Number of packets transmitted.", labels),
+		"txBytes":         colPromDesc(interfaceSubsystem, "transmit_bytes_total", "Number of bytes transmitted.", labels),
+		"txErrors":        colPromDesc(interfaceSubsystem, "transmit_errors_total", "Number of transmit errors.", labels),
+		"collisions":      colPromDesc(interfaceSubsystem, "collisions_total", "Number of collisions.", labels),
+	}
+}
+
+// Update implemented as per the Collector interface.
+func (c *interfaceCollector) Update(ch chan<- prometheus.Metric) error {
+	cmd := "show interface vrf all json"
+	jsonInterfaces, err := executeZebraCommand(cmd)
+	if err != nil {
+		return err
+	}
+	if err := processInterfaces(ch, jsonInterfaces, c.include, c.exclude, c.descriptions); err != nil {
+		return cmdOutputProcessError(cmd, string(jsonInterfaces), err)
+	}
+	return nil
+}
+
+func processInterfaces(ch chan<- prometheus.Metric, jsonInterfaces []byte, include, exclude *regexp.Regexp, ifaceDesc map[string]*prometheus.Desc) error {
+	var interfaces map[string]zebraInterface
+	if err := json.Unmarshal(jsonInterfaces, &interfaces); err != nil {
+		return err
+	}
+
+	for name, iface := range interfaces {
+		if include != nil && !include.MatchString(name) {
+			continue
+		}
+		if exclude != nil && exclude.MatchString(name) {
+			continue
+		}
+
+		labels := []string{name, iface.VrfName}
+
+		newGauge(ch, ifaceDesc["adminUp"], boolToFloat(iface.AdministrativeStatus == "up"), labels...)
+		if iface.OperationalStatus != "" {
+			newGauge(ch, ifaceDesc["operUp"], boolToFloat(iface.OperationalStatus == "up"), labels...)
+		}
+		newGauge(ch, ifaceDesc["linkDetection"], boolToFloat(iface.LinkDetection), labels...)
+		newGauge(ch, ifaceDesc["protodown"], boolToFloat(iface.Protodown), labels...)
+		for _, reason := range iface.ProtodownReasons {
+			newGauge(ch, ifaceDesc["protodownReason"], 1, append(labels, reason)...)
+		}
+		newGauge(ch, ifaceDesc["mtu"], float64(iface.MTU), labels...)
+		// zebra reports the speed of an interface it cannot determine as UINT32_MAX.
+		if iface.Speed != math.MaxUint32 {
+			newGauge(ch, ifaceDesc["speed"], float64(iface.Speed)*1e6/8, labels...)
+		}
+		newCounter(ch, ifaceDesc["linkUps"], float64(iface.LinkUps), labels...)
+		newCounter(ch, ifaceDesc["linkDowns"], float64(iface.LinkDowns), labels...)
+		newCounter(ch, ifaceDesc["rxPackets"], float64(iface.InputPackets), labels...)
+		newCounter(ch, ifaceDesc["rxBytes"], float64(iface.InputBytes), labels...)
+		newCounter(ch, ifaceDesc["rxDropped"], float64(iface.InputDropped), labels...)
+		newCounter(ch, ifaceDesc["rxErrors"], float64(iface.InputErrors), labels...)
+		newCounter(ch, ifaceDesc["txPackets"], float64(iface.OutputPackets), labels...)
+		newCounter(ch, ifaceDesc["txBytes"], float64(iface.OutputBytes), labels...)
+		newCounter(ch, ifaceDesc["txErrors"], float64(iface.OutputErrors), labels...)
+		newCounter(ch, ifaceDesc["collisions"], float64(iface.Collisions), labels...)
+	}
+	return nil
+}
+
+type zebraInterface struct {
+	AdministrativeStatus string           `json:"administrativeStatus"`
+	OperationalStatus    string           `json:"operationalStatus"`
+	LinkDetection        bool             `json:"linkDetection"`
+	LinkUps              uint64           `json:"linkUps"`
+	LinkDowns            uint64           `json:"linkDowns"`
+	VrfName              string           `json:"vrfName"`
+	MTU                  uint32           `json:"mtu"`
+	Speed                uint32           `json:"speed"`
+	Protodown            bool             `json:"protodown"`
+	ProtodownReasons     protodownReasons `json:"protodownReason"`
+	InputPackets         uint64           `json:"inputPackets"`
+	InputBytes           uint64           `json:"inputBytes"`
+	InputDropped         uint64           `json:"inputDropped"`
+	InputErrors          uint64           `json:"inputErrors"`
+	OutputPackets        uint64           `json:"outputPackets"`
+	OutputBytes          uint64           `json:"outputBytes"`
+	OutputErrors         uint64           `json:"outputErrors"`
+	Collisions           uint64           `json:"collisions"`
+}
+
+// protodownReasons is the list of reasons an interface is protocol down, which zebra reports as
+// a single string such as "(external,evpn-mh startup delay)".
+type protodownReasons []string
+
+func (r *protodownReasons) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*r = nil
+	for _, reason := range strings.Split(strings.Trim(raw, "()"), ",") {
+		if reason = strings.TrimSpace(reason); reason != "" {
+			*r = append(*r, reason)
+		}
+	}
+	return nil
+}
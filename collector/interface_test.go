@@ -0,0 +1,73 @@
+package collector
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestProcessInterfaces(t *testing.T) {
+	expected := map[string]float64{
+		"frr_interface_admin_up{iface=eth0,vrf=default}":                                  1,
+		"frr_interface_oper_up{iface=eth0,vrf=default}":                                   1,
+		"frr_interface_link_detection{iface=eth0,vrf=default}":                            1,
+		"frr_interface_protodown{iface=eth0,vrf=default}":                                 0,
+		"frr_interface_mtu_bytes{iface=eth0,vrf=default}":                                 1500,
+		"frr_interface_speed_bytes{iface=eth0,vrf=default}":                               1.25e9,
+		"frr_interface_link_ups_total{iface=eth0,vrf=default}":                            1,
+		"frr_interface_link_downs_total{iface=eth0,vrf=default}":                          0,
+		"frr_interface_receive_packets_total{iface=eth0,vrf=default}":                     123456,
+		"frr_interface_receive_bytes_total{iface=eth0,vrf=default}":                       98765432,
+		"frr_interface_receive_dropped_total{iface=eth0,vrf=default}":                     12,
+		"frr_interface_receive_errors_total{iface=eth0,vrf=default}":                      1,
+		"frr_interface_transmit_packets_total{iface=eth0,vrf=default}":                    65432,
+		"frr_interface_transmit_bytes_total{iface=eth0,vrf=default}":                      45678901,
+		"frr_interface_transmit_errors_total{iface=eth0,vrf=default}":                     0,
+		"frr_interface_collisions_total{iface=eth0,vrf=default}":                          0,
+		"frr_interface_admin_up{iface=swp1,vrf=red}":                                      1,
+		"frr_interface_oper_up{iface=swp1,vrf=red}":                                       0,
+		"frr_interface_link_detection{iface=swp1,vrf=red}":                                1,
+		"frr_interface_protodown{iface=swp1,vrf=red}":                                     1,
+		"frr_interface_protodown_reason{iface=swp1,reason=external,vrf=red}":              1,
+		"frr_interface_protodown_reason{iface=swp1,reason=evpn-mh startup delay,vrf=red}": 1,
+		"frr_interface_mtu_bytes{iface=swp1,vrf=red}":                                     9216,
+		"frr_interface_speed_bytes{iface=swp1,vrf=red}":                                   3.125e9,
+		"frr_interface_link_ups_total{iface=swp1,vrf=red}":                                3,
+		"frr_interface_link_downs_total{iface=swp1,vrf=red}":                              4,
+		"frr_interface_receive_packets_total{iface=swp1,vrf=red}":                         10,
+		"frr_interface_receive_bytes_total{iface=swp1,vrf=red}":                           1000,
+		"frr_interface_receive_dropped_total{iface=swp1,vrf=red}":                         0,
+		"frr_interface_receive_errors_total{iface=swp1,vrf=red}":                          0,
+		"frr_interface_transmit_packets_total{iface=swp1,vrf=red}":                        20,
+		"frr_interface_transmit_bytes_total{iface=swp1,vrf=red}":                          2000,
+		"frr_interface_transmit_errors_total{iface=swp1,vrf=red}":                         2,
+		"frr_interface_collisions_total{iface=swp1,vrf=red}":                              0,
+		// zebra cannot determine the speed of swp2, so it has no speed metric.
+		"frr_interface_admin_up{iface=swp2,vrf=default}":               1,
+		"frr_interface_oper_up{iface=swp2,vrf=default}":                0,
+		"frr_interface_link_detection{iface=swp2,vrf=default}":         1,
+		"frr_interface_protodown{iface=swp2,vrf=default}":              0,
+		"frr_interface_mtu_bytes{iface=swp2,vrf=default}":              9216,
+		"frr_interface_link_ups_total{iface=swp2,vrf=default}":         0,
+		"frr_interface_link_downs_total{iface=swp2,vrf=default}":       0,
+		"frr_interface_receive_packets_total{iface=swp2,vrf=default}":  0,
+		"frr_interface_receive_bytes_total{iface=swp2,vrf=default}":    0,
+		"frr_interface_receive_dropped_total{iface=swp2,vrf=default}":  0,
+		"frr_interface_receive_errors_total{iface=swp2,vrf=default}":   0,
+		"frr_interface_transmit_packets_total{iface=swp2,vrf=default}": 0,
+		"frr_interface_transmit_bytes_total{iface=swp2,vrf=default}":   0,
+		"frr_interface_transmit_errors_total{iface=swp2,vrf=default}":  0,
+		"frr_interface_collisions_total{iface=swp2,vrf=default}":       0,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	include := regexp.MustCompile(`^(eth|swp|vxlan)`)
+	exclude := regexp.MustCompile(`^vxlan`)
+	if err := processInterfaces(ch, readTestFixture(t, "show_interface_vrf_all.json"), include, exclude, getInterfaceDesc()); err != nil {
+		t.Errorf("error calling processInterfaces: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
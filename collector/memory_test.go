@@ -0,0 +1,55 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestProcessMemory(t *testing.T) {
+	expected := map[string]float64{
+		"frr_memory_heap_allocated_bytes{daemon=zebra}":                             6204 * 1024,
+		"frr_memory_heap_used_bytes{daemon=zebra}":                                  5336 * 1024,
+		"frr_memory_allocations{daemon=zebra,group=libfrr,type=Buffer}":             4,
+		"frr_memory_allocated_bytes{daemon=zebra,group=libfrr,type=Buffer}":         96,
+		"frr_memory_allocations{daemon=zebra,group=libfrr,type=Buffer data}":        1,
+		"frr_memory_allocated_bytes{daemon=zebra,group=libfrr,type=Buffer data}":    4120,
+		"frr_memory_allocations{daemon=zebra,group=libfrr,type=Host config}":        3,
+		"frr_memory_allocated_bytes{daemon=zebra,group=libfrr,type=Host config}":    72,
+		"frr_memory_allocations{daemon=zebra,group=Zebra,type=Route Entry}":         812,
+		"frr_memory_allocated_bytes{daemon=zebra,group=Zebra,type=Route Entry}":     64960,
+		"frr_memory_allocations{daemon=zebra,group=Zebra,type=RIB destination}":     540,
+		"frr_memory_allocated_bytes{daemon=zebra,group=Zebra,type=RIB destination}": 25920,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processMemory(ch, readTestFixture(t, "show_memory.txt"), "zebra", getMemoryDesc()); err != nil {
+		t.Errorf("error calling processMemory: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestProcessMemoryVtysh(t *testing.T) {
+	expected := map[string]float64{
+		"frr_memory_heap_allocated_bytes{daemon=bgpd}":                        2 * 1024 * 1024,
+		"frr_memory_heap_used_bytes{daemon=bgpd}":                             1024*1024 + 512,
+		"frr_memory_allocations{daemon=bgpd,group=libfrr,type=Buffer}":        2,
+		"frr_memory_allocated_bytes{daemon=bgpd,group=libfrr,type=Buffer}":    48,
+		"frr_memory_allocations{daemon=bgpd,group=libfrr,type=Host config}":   3,
+		"frr_memory_allocations{daemon=bgpd,group=BGP,type=BGP instance}":     1,
+		"frr_memory_allocated_bytes{daemon=bgpd,group=BGP,type=BGP instance}": 10312,
+		"frr_memory_heap_allocated_bytes{daemon=staticd}":                     512 * 1024,
+		"frr_memory_allocations{daemon=staticd,group=libfrr,type=Buffer}":     1,
+		"frr_memory_allocated_bytes{daemon=staticd,group=libfrr,type=Buffer}": 24,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processMemory(ch, readTestFixture(t, "show_memory_vtysh.txt"), "", getMemoryDesc()); err != nil {
+		t.Errorf("error calling processMemory: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
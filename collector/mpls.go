@@ -0,0 +1,169 @@
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"regexp"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	mplsSubsystem = "mpls"
+
+	mplsLabelChunk = regexp.MustCompile(`^Proto (\S+): \[(\d+)/(\d+)\]`)
+)
+
+func init() {
+	registerCollector(mplsSubsystem, disabledByDefault, NewMPLSCollector)
+}
+
+type mplsCollector struct {
+	logger       *slog.Logger
+	descriptions map[string]*prometheus.Desc
+}
+
+// NewMPLSCollector collects zebra MPLS label forwarding table metrics, implemented as per the Collector interface.
+func NewMPLSCollector(logger *slog.Logger) (Collector, error) {
+	return &mplsCollector{logger: logger, descriptions: getMPLSDesc()}, nil
+}
+
+func getMPLSDesc() map[string]*prometheus.Desc {
+	chunkLabels := []string{"protocol", "start", "end"}
+
+	return map[string]*prometheus.Desc{
+		"inLabels":                colPromDesc(mplsSubsystem, "in_labels", "Number of in-labels in the MPLS table owned by the protocol.", []string{"protocol"}),
+		"lsps":                    colPromDesc(mplsSubsystem, "lsps", "Number of LSPs in the MPLS table.", []string{"installed"}),
+		"lspsUninstalledNexthops": colPromDesc(mplsSubsystem, "lsps_uninstalled_nexthops", "Number of LSPs with at least one nexthop of the best distance that is not installed.", nil),
+		"chunkSize":               colPromDesc(mplsSubsystem, "label_chunk_size", "Number of labels in the label chunk allocated to the protocol by the label manager.", chunkLabels),
+		"chunkUsed":               colPromDesc(mplsSubsystem, "label_chunk_used", "Number of labels of the label chunk in use as in-labels in the MPLS table.", chunkLabels),
+	}
+}
+
+// Update implemented as per the Collector interface.
+func (c *mplsCollector) Update(ch chan<- prometheus.Metric) error {
+	cmd := "show mpls table json"
+	jsonMPLS, err := executeZebraCommand(cmd)
+	if err != nil {
+		return err
+	}
+	inLabels, err := processMPLSTable(ch, jsonMPLS, c.descriptions)
+	if err != nil {
+		return cmdOutputProcessError(cmd, string(jsonMPLS), err)
+	}
+
+	cmd = "show debugging label-table"
+	output, err := executeZebraCommand(cmd)
+	if err != nil {
+		return err
+	}
+	if err := processMPLSLabelChunks(ch, output, inLabels, c.descriptions); err != nil {
+		return cmdOutputProcessError(cmd, string(output), err)
+	}
+	return nil
+}
+
+// processMPLSTable exports the LSP metrics of 'show mpls table json' and returns the in-labels of
+// the table.
+func processMPLSTable(ch chan<- prometheus.Metric, jsonMPLS []byte, mplsDesc map[string]*prometheus.Desc) ([]uint32, error) {
+	var lsps map[string]mplsLSP
+	if err := json.Unmarshal(jsonMPLS, &lsps); err != nil {
+		return nil, err
+	}
+
+	inLabels := make([]uint32, 0, len(lsps))
+	protocols := make(map[string]float64)
+	installed := map[bool]float64{true: 0, false: 0}
+	uninstalledNexthops := 0.0
+
+	for _, lsp := range lsps {
+		inLabels = append(inLabels, lsp.InLabel)
+		installed[lsp.Installed]++
+
+		// An in-label can be shared by nexthops of multiple protocols, count it once for each.
+		owners := make(map[string]bool)
+		for _, nh := range lsp.Nexthops {
+			owners[nh.Type] = true
+		}
+		// zebra only installs the nexthops of the best, i.e. lowest, distance, so nexthops of other protocols are
+		// expected not to be installed.
+		best := uint32(math.MaxUint32)
+		for _, nh := range lsp.Nexthops {
+			best = min(best, nh.Distance)
+		}
+		incomplete := false
+		for _, nh := range lsp.Nexthops {
+			if nh.Distance == best && !nh.Installed {
+				incomplete = true
+			}
+		}
+		for protocol := range owners {
+			protocols[protocol]++
+		}
+		if incomplete {
+			uninstalledNexthops++
+		}
+	}
+
+	for protocol, count := range protocols {
+		newGauge(ch, mplsDesc["inLabels"], count, protocol)
+	}
+	for state, count := range installed {
+		newGauge(ch, mplsDesc["lsps"], count, strconv.FormatBool(state))
+	}
+	newGauge(ch, mplsDesc["lspsUninstalledNexthops"], uninstalledNexthops)
+	return inLabels, nil
+}
+
+// processMPLSLabelChunks parses the chunks allocated by the label manager of 'show debugging label-table', for
+// example:
+//
+//	Proto ldp: [16/79]
+//	Proto bgp: [80/143]
+//
+// and exports the size of each chunk along with how many of inLabels fall within it.
+func processMPLSLabelChunks(ch chan<- prometheus.Metric, output []byte, inLabels []uint32, mplsDesc map[string]*prometheus.Desc) error {
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		match := mplsLabelChunk.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		start, err := strconv.ParseUint(match[2], 10, 32)
+		if err != nil {
+			return err
+		}
+		end, err := strconv.ParseUint(match[3], 10, 32)
+		if err != nil {
+			return err
+		}
+
+		used := 0.0
+		for _, label := range inLabels {
+			if uint64(label) >= start && uint64(label) <= end {
+				used++
+			}
+		}
+
+		labels := []string{match[1], match[2], match[3]}
+		newGauge(ch, mplsDesc["chunkSize"], float64(end-start+1), labels...)
+		newGauge(ch, mplsDesc["chunkUsed"], used, labels...)
+	}
+	return scanner.Err()
+}
+
+type mplsLSP struct {
+	InLabel   uint32        `json:"inLabel"`
+	Installed bool          `json:"installed"`
+	Nexthops  []mplsNexthop `json:"nexthops"`
+}
+
+type mplsNexthop struct {
+	Type      string `json:"type"`
+	Distance  uint32 `json:"distance"`
+	Installed bool   `json:"installed"`
+}
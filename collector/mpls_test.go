@@ -0,0 +1,48 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestProcessMPLSTable(t *testing.T) {
+	expected := map[string]float64{
+		"frr_mpls_in_labels{protocol=LDP}":       2,
+		"frr_mpls_in_labels{protocol=Static}":    1,
+		"frr_mpls_in_labels{protocol=BGP}":       1,
+		"frr_mpls_in_labels{protocol=SR (OSPF)}": 1,
+		"frr_mpls_lsps{installed=true}":          3,
+		"frr_mpls_lsps{installed=false}":         1,
+		"frr_mpls_lsps_uninstalled_nexthops{}":   1,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	inLabels, err := processMPLSTable(ch, readTestFixture(t, "show_mpls_table.json"), getMPLSDesc())
+	if err != nil {
+		t.Errorf("error calling processMPLSTable: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+	if len(inLabels) != 4 {
+		t.Errorf("expected 4 in-labels, got %d", len(inLabels))
+	}
+}
+
+func TestProcessMPLSLabelChunks(t *testing.T) {
+	expected := map[string]float64{
+		"frr_mpls_label_chunk_size{end=79,protocol=ldp,start=16}":  64,
+		"frr_mpls_label_chunk_used{end=79,protocol=ldp,start=16}":  2,
+		"frr_mpls_label_chunk_size{end=143,protocol=bgp,start=80}": 64,
+		"frr_mpls_label_chunk_used{end=143,protocol=bgp,start=80}": 1,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processMPLSLabelChunks(ch, readTestFixture(t, "show_debugging_label_table.txt"), []uint32{16, 17, 80, 16001}, getMPLSDesc()); err != nil {
+		t.Errorf("error calling processMPLSLabelChunks: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
@@ -0,0 +1,135 @@
+package collector
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strconv"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	nhgSubsystem = "nhg"
+	nhgPerGroup  = kingpin.Flag("collector.nhg.per-nhg", "Enable per nexthop group reference and nexthop count metrics. Not recommended on devices with many nexthop groups (default: disabled).").Default("False").Bool()
+
+	nhgRefCountBuckets = []float64{0, 1, 2, 5, 10, 50, 100, 500, 1000}
+	nhgNexthopBuckets  = []float64{1, 2, 4, 8, 16, 32, 64, 128}
+)
+
+func init() {
+	registerCollector(nhgSubsystem, disabledByDefault, NewNHGCollector)
+}
+
+type nhgCollector struct {
+	logger       *slog.Logger
+	descriptions map[string]*prometheus.Desc
+}
+
+// NewNHGCollector collects zebra nexthop group metrics, implemented as per the Collector interface.
+func NewNHGCollector(logger *slog.Logger) (Collector, error) {
+	return &nhgCollector{logger: logger, descriptions: getNHGDesc()}, nil
+}
+
+func getNHGDesc() map[string]*prometheus.Desc {
+	countLabels := []string{"protocol", "valid", "installed"}
+	groupLabels := []string{"id", "protocol", "vrf"}
+
+	return map[string]*prometheus.Desc{
+		"groups":        colPromDesc(nhgSubsystem, "groups", "Number of nexthop groups by owning protocol, validity and installed state.", countLabels),
+		"refCount":      colPromDesc(nhgSubsystem, "reference_count", "Distribution of the reference count of nexthop groups.", nil),
+		"nexthops":      colPromDesc(nhgSubsystem, "nexthops", "Distribution of the number of nexthops in nexthop groups.", nil),
+		"groupRefCount": colPromDesc(nhgSubsystem, "group_reference_count", "Reference count of the nexthop group.", groupLabels),
+		"groupNexthops": colPromDesc(nhgSubsystem, "group_nexthops", "Number of nexthops in the nexthop group.", groupLabels),
+	}
+}
+
+// Update implemented as per the Collector interface.
+func (c *nhgCollector) Update(ch chan<- prometheus.Metric) error {
+	cmd := "show nexthop-group rib json"
+	jsonNHG, err := executeZebraCommand(cmd)
+	if err != nil {
+		return err
+	}
+	if err := processNHGs(ch, jsonNHG, *nhgPerGroup, c.descriptions); err != nil {
+		return cmdOutputProcessError(cmd, string(jsonNHG), err)
+	}
+	return nil
+}
+
+func processNHGs(ch chan<- prometheus.Metric, jsonNHG []byte, perGroup bool, nhgDesc map[string]*prometheus.Desc) error {
+	var nhgs map[string]nhg
+	if err := json.Unmarshal(jsonNHG, &nhgs); err != nil {
+		return err
+	}
+
+	type groupKey struct {
+		protocol, valid, installed string
+	}
+	groups := make(map[groupKey]float64)
+	refCounts := newConstHistogram(nhgRefCountBuckets)
+	nexthops := newConstHistogram(nhgNexthopBuckets)
+
+	for id, group := range nhgs {
+		groups[groupKey{group.Type, strconv.FormatBool(group.Valid), strconv.FormatBool(group.Installed)}]++
+		refCounts.observe(float64(group.RefCount))
+		nexthops.observe(float64(len(group.Nexthops)))
+
+		if perGroup {
+			labels := []string{id, group.Type, group.VRF}
+			newGauge(ch, nhgDesc["groupRefCount"], float64(group.RefCount), labels...)
+			newGauge(ch, nhgDesc["groupNexthops"], float64(len(group.Nexthops)), labels...)
+		}
+	}
+
+	for key, count := range groups {
+		newGauge(ch, nhgDesc["groups"], count, key.protocol, key.valid, key.installed)
+	}
+	ch <- refCounts.metric(nhgDesc["refCount"])
+	ch <- nexthops.metric(nhgDesc["nexthops"])
+	return nil
+}
+
+// constHistogram accumulates observations for a histogram that is exported with
+// prometheus.MustNewConstHistogram.
+type constHistogram struct {
+	buckets map[float64]uint64
+	count   uint64
+	sum     float64
+}
+
+func newConstHistogram(upperBounds []float64) *constHistogram {
+	h := &constHistogram{buckets: make(map[float64]uint64, len(upperBounds))}
+	for _, b := range upperBounds {
+		h.buckets[b] = 0
+	}
+	return h
+}
+
+func (h *constHistogram) observe(v float64) {
+	h.observeN(v, 1)
+}
+
+// observeN observes v n times.
+func (h *constHistogram) observeN(v float64, n uint64) {
+	for b := range h.buckets {
+		if v <= b {
+			h.buckets[b] += n
+		}
+	}
+	h.count += n
+	h.sum += v * float64(n)
+}
+
+func (h *constHistogram) metric(desc *prometheus.Desc, labels ...string) prometheus.Metric {
+	return prometheus.MustNewConstHistogram(desc, h.count, h.sum, h.buckets, labels...)
+}
+
+type nhg struct {
+	Type      string            `json:"type"`
+	RefCount  uint32            `json:"refCount"`
+	VRF       string            `json:"vrf"`
+	Valid     bool              `json:"valid"`
+	Installed bool              `json:"installed"`
+	Nexthops  []json.RawMessage `json:"nexthops"`
+}
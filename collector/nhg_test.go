@@ -0,0 +1,52 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestProcessNHGs(t *testing.T) {
+	expected := map[string]float64{
+		"frr_nhg_groups{installed=true,protocol=zebra,valid=true}":        2,
+		"frr_nhg_groups{installed=true,protocol=bgp,valid=true}":          1,
+		"frr_nhg_groups{installed=false,protocol=bgp,valid=false}":        1,
+		"frr_nhg_reference_count_bucket{le=0}":                            1,
+		"frr_nhg_reference_count_bucket{le=1}":                            2,
+		"frr_nhg_reference_count_bucket{le=2}":                            2,
+		"frr_nhg_reference_count_bucket{le=5}":                            3,
+		"frr_nhg_reference_count_bucket{le=10}":                           3,
+		"frr_nhg_reference_count_bucket{le=50}":                           3,
+		"frr_nhg_reference_count_bucket{le=100}":                          3,
+		"frr_nhg_reference_count_bucket{le=500}":                          4,
+		"frr_nhg_reference_count_bucket{le=1000}":                         4,
+		"frr_nhg_reference_count_sum{}":                                   124,
+		"frr_nhg_reference_count_count{}":                                 4,
+		"frr_nhg_nexthops_bucket{le=1}":                                   3,
+		"frr_nhg_nexthops_bucket{le=2}":                                   4,
+		"frr_nhg_nexthops_bucket{le=4}":                                   4,
+		"frr_nhg_nexthops_bucket{le=8}":                                   4,
+		"frr_nhg_nexthops_bucket{le=16}":                                  4,
+		"frr_nhg_nexthops_bucket{le=32}":                                  4,
+		"frr_nhg_nexthops_bucket{le=64}":                                  4,
+		"frr_nhg_nexthops_bucket{le=128}":                                 4,
+		"frr_nhg_nexthops_sum{}":                                          5,
+		"frr_nhg_nexthops_count{}":                                        4,
+		"frr_nhg_group_reference_count{id=12,protocol=zebra,vrf=default}": 3,
+		"frr_nhg_group_nexthops{id=12,protocol=zebra,vrf=default}":        1,
+		"frr_nhg_group_reference_count{id=13,protocol=zebra,vrf=default}": 1,
+		"frr_nhg_group_nexthops{id=13,protocol=zebra,vrf=default}":        1,
+		"frr_nhg_group_reference_count{id=70,protocol=bgp,vrf=default}":   120,
+		"frr_nhg_group_nexthops{id=70,protocol=bgp,vrf=default}":          2,
+		"frr_nhg_group_reference_count{id=71,protocol=bgp,vrf=red}":       0,
+		"frr_nhg_group_nexthops{id=71,protocol=bgp,vrf=red}":              1,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processNHGs(ch, readTestFixture(t, "show_nexthop_group_rib.json"), true, getNHGDesc()); err != nil {
+		t.Errorf("error calling processNHGs: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
@@ -0,0 +1,193 @@
+package collector
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const openFabricSubsystem = "openfabric"
+
+func init() {
+	registerCollector(openFabricSubsystem, disabledByDefault, NewOpenFabricCollector)
+}
+
+type openFabricCollector struct {
+	logger       *slog.Logger
+	descriptions map[string]*prometheus.Desc
+}
+
+// NewOpenFabricCollector collects OpenFabric (fabricd) metrics, implemented as per the Collector interface.
+func NewOpenFabricCollector(logger *slog.Logger) (Collector, error) {
+	return &openFabricCollector{logger: logger, descriptions: getOpenFabricDesc()}, nil
+}
+
+func getOpenFabricDesc() map[string]*prometheus.Desc {
+	areaLabels := []string{"area"}
+	levelLabels := []string{"area", "level"}
+	adjLabels := []string{"area", "iface", "neighbor", "level"}
+
+	return map[string]*prometheus.Desc{
+		"adjacencies":        colPromDesc(openFabricSubsystem, "adjacencies", "Number of adjacencies in the area.", areaLabels),
+		"adjacencyState":     colPromDesc(openFabricSubsystem, "adjacency_state", "State of the adjacency (1 = Up, 0 = Initializing or Down).", adjLabels),
+		"lspCount":           colPromDesc(openFabricSubsystem, "lsp_count", "Number of LSPs in the link state database.", levelLabels),
+		"lspRegenerated":     colPromDesc(openFabricSubsystem, "lsp_regenerated_total", "Number of times the local LSP was regenerated.", levelLabels),
+		"lspPurged":          colPromDesc(openFabricSubsystem, "lsp_purged_total", "Number of LSPs purged.", levelLabels),
+		"spfRuns":            colPromDesc(openFabricSubsystem, "spf_runs_total", "Number of SPF calculations run.", levelLabels),
+		"spfLastRunDuration": colPromDesc(openFabricSubsystem, "spf_last_run_duration_seconds", "Duration of the last SPF calculation.", levelLabels),
+		"spfPending":         colPromDesc(openFabricSubsystem, "spf_pending", "Whether an SPF calculation is pending (1 = pending, 0 = not pending).", levelLabels),
+	}
+}
+
+// Update implemented as per the Collector interface.
+func (c *openFabricCollector) Update(ch chan<- prometheus.Metric) error {
+	steps := []struct {
+		cmd       string
+		processor func(chan<- prometheus.Metric, []byte, map[string]*prometheus.Desc) error
+	}{
+		{cmd: "show openfabric summary json", processor: processOpenFabricSummary},
+		{cmd: "show openfabric neighbor json", processor: processOpenFabricNeighbors},
+		{cmd: "show openfabric database json", processor: processOpenFabricDatabase},
+	}
+
+	for _, s := range steps {
+		output, err := executeFabricdCommand(s.cmd)
+		if err != nil {
+			return err
+		}
+		if err := s.processor(ch, output, c.descriptions); err != nil {
+			return cmdOutputProcessError(s.cmd, string(output), err)
+		}
+	}
+	return nil
+}
+
+func processOpenFabricSummary(ch chan<- prometheus.Metric, jsonSummary []byte, desc map[string]*prometheus.Desc) error {
+	var summary openFabricSummary
+	if err := json.Unmarshal(jsonSummary, &summary); err != nil {
+		return err
+	}
+
+	for _, area := range summary.Areas {
+		for _, level := range area.Levels {
+			labels := []string{string(area.Area), strconv.Itoa(level.ID)}
+
+			pending := 0.0
+			if level.SPF == "pending" {
+				pending = 1
+			}
+
+			newCounter(ch, desc["lspRegenerated"], float64(level.LSPRegenerated), labels...)
+			newCounter(ch, desc["lspPurged"], float64(level.LSPPurged), labels...)
+			newCounter(ch, desc["spfRuns"], float64(level.LastRunCount), labels...)
+			newGauge(ch, desc["spfLastRunDuration"], float64(level.LastRunDurationUsec)/1e6, labels...)
+			newGauge(ch, desc["spfPending"], pending, labels...)
+		}
+	}
+	return nil
+}
+
+func processOpenFabricNeighbors(ch chan<- prometheus.Metric, jsonNeighbors []byte, desc map[string]*prometheus.Desc) error {
+	var neighbors openFabricNeighbors
+	if err := json.Unmarshal(jsonNeighbors, &neighbors); err != nil {
+		return err
+	}
+
+	for _, area := range neighbors.Areas {
+		adjacencies := 0.0
+		for _, circuit := range area.Circuits {
+			// Circuits without an adjacency are listed without a neighbor.
+			if circuit.Adj == "" {
+				continue
+			}
+			adjacencies++
+
+			state := 0.0
+			if circuit.State == "Up" {
+				state = 1
+			}
+			newGauge(ch, desc["adjacencyState"], state, string(area.Area), circuit.Interface, circuit.Adj, strconv.Itoa(circuit.Level))
+		}
+		newGauge(ch, desc["adjacencies"], adjacencies, string(area.Area))
+	}
+	return nil
+}
+
+func processOpenFabricDatabase(ch chan<- prometheus.Metric, jsonDatabase []byte, desc map[string]*prometheus.Desc) error {
+	var database openFabricDatabase
+	if err := json.Unmarshal(jsonDatabase, &database); err != nil {
+		return err
+	}
+
+	for _, area := range database.Areas {
+		for _, level := range area.Levels {
+			count := level.Count
+			if count == 0 {
+				count = uint32(len(level.LSPs))
+			}
+			newGauge(ch, desc["lspCount"], float64(count), string(area.Area), strconv.Itoa(level.ID))
+		}
+	}
+	return nil
+}
+
+// openFabricArea is the area tag, which depending on the FRR version and command is either a
+// plain string or an object with a name key.
+type openFabricArea string
+
+func (a *openFabricArea) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*a = openFabricArea(name)
+		return nil
+	}
+
+	var obj struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	*a = openFabricArea(obj.Name)
+	return nil
+}
+
+type openFabricSummary struct {
+	Areas []struct {
+		Area   openFabricArea `json:"area"`
+		Levels []struct {
+			ID                  int    `json:"id"`
+			LSPRegenerated      uint64 `json:"lsp0-regenerated"`
+			LSPPurged           uint64 `json:"lsp-purged"`
+			SPF                 string `json:"spf"`
+			LastRunDurationUsec uint64 `json:"last-run-duration-usec"`
+			LastRunCount        uint64 `json:"last-run-count"`
+		} `json:"levels"`
+	} `json:"areas"`
+}
+
+type openFabricNeighbors struct {
+	Areas []struct {
+		Area     openFabricArea `json:"area"`
+		Circuits []struct {
+			Circuit   int    `json:"circuit"`
+			Adj       string `json:"adj"`
+			Interface string `json:"interface"`
+			Level     int    `json:"level"`
+			State     string `json:"state"`
+		} `json:"circuits"`
+	} `json:"areas"`
+}
+
+type openFabricDatabase struct {
+	Areas []struct {
+		Area   openFabricArea `json:"area"`
+		Levels []struct {
+			ID    int               `json:"id"`
+			Count uint32            `json:"count"`
+			LSPs  []json.RawMessage `json:"lsps"`
+		} `json:"levels"`
+	} `json:"areas"`
+}
@@ -0,0 +1,55 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestProcessOpenFabricSummary(t *testing.T) {
+	expected := map[string]float64{
+		"frr_openfabric_lsp_regenerated_total{area=1,level=2}":         14,
+		"frr_openfabric_lsp_purged_total{area=1,level=2}":              1,
+		"frr_openfabric_spf_runs_total{area=1,level=2}":                23,
+		"frr_openfabric_spf_last_run_duration_seconds{area=1,level=2}": 0.00025,
+		"frr_openfabric_spf_pending{area=1,level=2}":                   0,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processOpenFabricSummary(ch, readTestFixture(t, "show_openfabric_summary.json"), getOpenFabricDesc()); err != nil {
+		t.Errorf("error calling processOpenFabricSummary: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestProcessOpenFabricNeighbors(t *testing.T) {
+	expected := map[string]float64{
+		"frr_openfabric_adjacencies{area=1}":                                        2,
+		"frr_openfabric_adjacency_state{area=1,iface=eth0,level=2,neighbor=spine1}": 1,
+		"frr_openfabric_adjacency_state{area=1,iface=eth1,level=2,neighbor=spine2}": 0,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processOpenFabricNeighbors(ch, readTestFixture(t, "show_openfabric_neighbor.json"), getOpenFabricDesc()); err != nil {
+		t.Errorf("error calling processOpenFabricNeighbors: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestProcessOpenFabricDatabase(t *testing.T) {
+	expected := map[string]float64{
+		"frr_openfabric_lsp_count{area=1,level=2}": 3,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processOpenFabricDatabase(ch, readTestFixture(t, "show_openfabric_database.json"), getOpenFabricDesc()); err != nil {
+		t.Errorf("error calling processOpenFabricDatabase: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
@@ -27,6 +27,7 @@ type ospfCollector struct {
 	ospfNeighDescriptions      map[string]*prometheus.Desc
 	ospfDataMaxAgeDescriptions map[string]*prometheus.Desc
 	instanceIDs                []int
+	neighStates                *stateTracker
 }
 
 // NewOSPFCollector  collects OSPF metrics, implemented as per the Collector interface.
@@ -51,11 +52,12 @@ func NewOSPFCollector(logger *slog.Logger) (Collector, error) {
 			instanceIDs = append(instanceIDs, i)
 		}
 	}
-	return &ospfCollector{logger: logger, instanceIDs: instanceIDs, ospfIfaceDescriptions: getOSPFIfaceDesc(), ospfDescriptions: getOSPFDesc(), ospfNeighDescriptions: getOSPFNeighDesc(), ospfDataMaxAgeDescriptions: getOSPFDataMaxAgeDesc()}, nil
+	return &ospfCollector{logger: logger, instanceIDs: instanceIDs, ospfIfaceDescriptions: getOSPFIfaceDesc(), ospfDescriptions: getOSPFDesc(), ospfNeighDescriptions: getOSPFNeighDesc(), ospfDataMaxAgeDescriptions: getOSPFDataMaxAgeDesc(), neighStates: newStateTracker(ospfSubsystem, ospfSubsystem, "neighbor", getOSPFNeighLabels())}, nil
 }
 
 // Update satisfies Collector.
 func (c *ospfCollector) Update(ch chan<- prometheus.Metric) error {
+	neighStates := c.neighStates.begin()
 	steps := []struct {
 		cmd       string
 		desc      map[string]*prometheus.Desc
@@ -72,9 +74,11 @@ func (c *ospfCollector) Update(ch chan<- prometheus.Metric) error {
 			processor: processOSPFInterface,
 		},
 		{
-			cmd:       "show ip ospf vrf all neighbor json",
-			desc:      c.ospfNeighDescriptions,
-			processor: processOSPFNeigh,
+			cmd:  "show ip ospf vrf all neighbor json",
+			desc: c.ospfNeighDescriptions,
+			processor: func(ch chan<- prometheus.Metric, jsonOSPFNeigh []byte, ospfDesc map[string]*prometheus.Desc, instanceID int) error {
+				return processOSPFNeigh(ch, jsonOSPFNeigh, ospfDesc, instanceID, neighStates)
+			},
 		},
 		{
 			cmd:       "show ip ospf vrf all database max-age json",
@@ -88,6 +92,7 @@ func (c *ospfCollector) Update(ch chan<- prometheus.Metric) error {
 			return err
 		}
 	}
+	c.neighStates.collect(ch, neighStates)
 	return nil
 }
 
@@ -150,14 +155,17 @@ func getOSPFDesc() map[string]*prometheus.Desc {
 	}
 }
 
-func getOSPFNeighDesc() map[string]*prometheus.Desc {
+func getOSPFNeighLabels() []string {
 	var labels []string
 	if len(*frrOSPFInstances) > 0 {
 		labels = append(labels, "instance")
 	}
-	labels = append(labels, "vrf", "neighbor", "iface", "local_address", "remote_address")
+	return append(labels, "vrf", "neighbor", "iface", "local_address", "remote_address")
+}
+
+func getOSPFNeighDesc() map[string]*prometheus.Desc {
 	return map[string]*prometheus.Desc{
-		"ospfNeighState": colPromDesc(ospfSubsystem, "neighbor_state", "OSPF neighbor state (1=Down, 2=Init, 3=2-Way, 4=ExStart, 5=Exchange, 6=Loading, 7=Full).", labels),
+		"ospfNeighState": colPromDesc(ospfSubsystem, "neighbor_state", "OSPF neighbor state (1=Down, 2=Init, 3=2-Way, 4=ExStart, 5=Exchange, 6=Loading, 7=Full).", getOSPFNeighLabels()),
 	}
 }
 
@@ -292,21 +300,21 @@ type ospfArea struct {
 	LsaNssaNumber    uint32
 }
 
-func processOSPFNeigh(ch chan<- prometheus.Metric, jsonOSPFNeigh []byte, ospfDesc map[string]*prometheus.Desc, instanceID int) error {
+func processOSPFNeigh(ch chan<- prometheus.Metric, jsonOSPFNeigh []byte, ospfDesc map[string]*prometheus.Desc, instanceID int, neighStates *stateObservations) error {
 	var vrfNeighs map[string]vrfNeighbors
 	if err := json.Unmarshal(jsonOSPFNeigh, &vrfNeighs); err != nil {
 		return fmt.Errorf("cannot unmarshal ospf neighbor json: %w", err)
 	}
 	for vrfName, vrfData := range vrfNeighs {
 		for neighborName, neighbors := range vrfData.Neighbors {
-			ospfNeighMetrics(ch, neighborName, neighbors, vrfName, ospfDesc, instanceID)
+			ospfNeighMetrics(ch, neighborName, neighbors, vrfName, ospfDesc, instanceID, neighStates)
 		}
 	}
 
 	return nil
 }
 
-func ospfNeighMetrics(ch chan<- prometheus.Metric, neighborName string, neighbors []ospfNeighbor, vrfName string, ospfDesc map[string]*prometheus.Desc, instanceID int) {
+func ospfNeighMetrics(ch chan<- prometheus.Metric, neighborName string, neighbors []ospfNeighbor, vrfName string, ospfDesc map[string]*prometheus.Desc, instanceID int, neighStates *stateObservations) {
 	var labels []string
 	if instanceID != 0 {
 		labels = append(labels, strconv.Itoa(instanceID))
@@ -332,7 +340,9 @@ func ospfNeighMetrics(ch chan<- prometheus.Metric, neighborName string, neighbor
 		default:
 			continue
 		}
-		newGauge(ch, ospfDesc["ospfNeighState"], state, append(labels, neighbor.IfaceName, neighbor.LocalAddress, neighbor.RemoteAddress)...)
+		neighLabels := append(append([]string{}, labels...), neighbor.IfaceName, neighbor.LocalAddress, neighbor.RemoteAddress)
+		newGauge(ch, ospfDesc["ospfNeighState"], state, neighLabels...)
+		neighStates.observe(neighbor.State, neighLabels...)
 	}
 }
 
@@ -128,7 +128,9 @@ func TestProcessOSPFNeigh(t *testing.T) {
 	runOSPFTest(
 		t,
 		"show_ip_ospf_vrf_all_neighbors.json",
-		processOSPFNeigh,
+		func(ch chan<- prometheus.Metric, jsonOSPFNeigh []byte, ospfDesc map[string]*prometheus.Desc, instanceID int) error {
+			return processOSPFNeigh(ch, jsonOSPFNeigh, ospfDesc, instanceID, nil)
+		},
 		getOSPFNeighDesc,
 		expected,
 	)
@@ -20,16 +20,23 @@ type rpkiCollector struct {
 	descriptions map[string]*prometheus.Desc
 }
 
-// NewRPKICollector collects RPKI cache-connection metrics, implemented as per the Collector interface.
+// NewRPKICollector collects RPKI cache-connection, ROA table and configuration metrics, implemented as per the Collector interface.
 func NewRPKICollector(logger *slog.Logger) (Collector, error) {
 	return &rpkiCollector{logger: logger, descriptions: getRPKIDesc()}, nil
 }
 
 func getRPKIDesc() map[string]*prometheus.Desc {
 	labels := []string{"vrf", "mode", "host", "port"}
+	vrfLabels := []string{"vrf"}
 	return map[string]*prometheus.Desc{
 		"cacheState":      colPromDesc(rpkiSubsystem, "cache_state", "State of the RPKI cache connection (1 = connected, 0 = disconnected).", labels),
 		"cachePreference": colPromDesc(rpkiSubsystem, "cache_preference", "Preference value of the RPKI cache connection.", labels),
+		"roaPrefixes":     colPromDesc(rpkiSubsystem, "roa_prefixes", "Number of ROA prefixes loaded from the RPKI caches.", []string{"vrf", "afi"}),
+		"enabled":         colPromDesc(rpkiSubsystem, "enabled", "Whether RPKI is running (1 = running, 0 = not running).", vrfLabels),
+		"cacheServers":    colPromDesc(rpkiSubsystem, "cache_servers", "Number of configured RPKI cache servers.", vrfLabels),
+		"pollingPeriod":   colPromDesc(rpkiSubsystem, "polling_period_seconds", "Configured interval at which the RPKI caches are polled for new data.", vrfLabels),
+		"retryInterval":   colPromDesc(rpkiSubsystem, "retry_interval_seconds", "Configured interval after which a failed RPKI cache poll is retried.", vrfLabels),
+		"expireInterval":  colPromDesc(rpkiSubsystem, "expire_interval_seconds", "Configured interval after which data from an RPKI cache that can no longer be reached expires.", vrfLabels),
 	}
 }
 
@@ -40,24 +47,46 @@ func (c *rpkiCollector) Update(ch chan<- prometheus.Metric) error {
 		return err
 	}
 
-	for _, vrf := range vrfs {
-		var cmd string
-		if vrf == "default" {
-			cmd = "show rpki cache-connection json"
-		} else {
-			cmd = fmt.Sprintf("show rpki cache-connection vrf %s json", vrf)
-		}
-
-		output, err := executeBGPCommand(cmd)
-		if err != nil {
-			return err
-		}
-		if len(output) == 0 {
-			continue
-		}
+	type step struct {
+		cmd       string
+		processor func(chan<- prometheus.Metric, []byte, string, map[string]*prometheus.Desc) error
+		// optional commands are not available in older versions of bgpd, so their failure is not an error.
+		optional bool
+	}
+	steps := []step{
+		{cmd: "cache-connection", processor: processRPKICacheConnection},
+		{cmd: "prefix-count", processor: processRPKIPrefixCount, optional: true},
+		{cmd: "configuration", processor: processRPKIConfiguration, optional: true},
+	}
 
-		if err := processRPKICacheConnection(ch, output, vrf, c.descriptions); err != nil {
-			return cmdOutputProcessError(cmd, string(output), err)
+	for _, vrf := range vrfs {
+		for _, s := range steps {
+			var cmd string
+			if vrf == "default" {
+				cmd = fmt.Sprintf("show rpki %s json", s.cmd)
+			} else {
+				cmd = fmt.Sprintf("show rpki %s vrf %s json", s.cmd, vrf)
+			}
+
+			output, err := executeBGPCommand(cmd)
+			if err != nil {
+				if s.optional {
+					c.logger.Debug("RPKI command not available", "cmd", cmd, "err", err)
+					continue
+				}
+				return err
+			}
+			if len(output) == 0 {
+				continue
+			}
+			if s.optional && !json.Valid(output) {
+				c.logger.Debug("RPKI command not available", "cmd", cmd, "output", string(output))
+				continue
+			}
+
+			if err := s.processor(ch, output, vrf, c.descriptions); err != nil {
+				return cmdOutputProcessError(cmd, string(output), err)
+			}
 		}
 	}
 	return nil
@@ -83,6 +112,31 @@ func processRPKICacheConnection(ch chan<- prometheus.Metric, jsonRPKI []byte, vr
 	return nil
 }
 
+func processRPKIPrefixCount(ch chan<- prometheus.Metric, jsonRPKI []byte, vrf string, rpkiDesc map[string]*prometheus.Desc) error {
+	var prefixCount rpkiPrefixCount
+	if err := json.Unmarshal(jsonRPKI, &prefixCount); err != nil {
+		return err
+	}
+
+	newGauge(ch, rpkiDesc["roaPrefixes"], float64(prefixCount.IPv4), vrf, "ipv4")
+	newGauge(ch, rpkiDesc["roaPrefixes"], float64(prefixCount.IPv6), vrf, "ipv6")
+	return nil
+}
+
+func processRPKIConfiguration(ch chan<- prometheus.Metric, jsonRPKI []byte, vrf string, rpkiDesc map[string]*prometheus.Desc) error {
+	var config rpkiConfiguration
+	if err := json.Unmarshal(jsonRPKI, &config); err != nil {
+		return err
+	}
+
+	newGauge(ch, rpkiDesc["enabled"], boolToFloat(config.Enabled), vrf)
+	newGauge(ch, rpkiDesc["cacheServers"], float64(config.ServersConfigured), vrf)
+	newGauge(ch, rpkiDesc["pollingPeriod"], float64(config.PollingPeriod), vrf)
+	newGauge(ch, rpkiDesc["retryInterval"], float64(config.RetryInterval), vrf)
+	newGauge(ch, rpkiDesc["expireInterval"], float64(config.ExpireInterval), vrf)
+	return nil
+}
+
 type rpkiCacheConnection struct {
 	ConnectedGroup int              `json:"connectedGroup"`
 	Connections    []rpkiConnection `json:"connections"`
@@ -95,3 +149,16 @@ type rpkiConnection struct {
 	Preference int    `json:"preference"`
 	State      string `json:"state"`
 }
+
+type rpkiPrefixCount struct {
+	IPv4 uint64 `json:"numberOfIpv4Prefixes"`
+	IPv6 uint64 `json:"numberOfIpv6Prefixes"`
+}
+
+type rpkiConfiguration struct {
+	Enabled           bool   `json:"enabled"`
+	ServersConfigured uint32 `json:"serversConfigured"`
+	PollingPeriod     uint32 `json:"pollingPeriodSeconds"`
+	RetryInterval     uint32 `json:"retryIntervalSeconds"`
+	ExpireInterval    uint32 `json:"expireIntervalSeconds"`
+}
@@ -39,3 +39,36 @@ func TestProcessRPKICacheConnectionVRF(t *testing.T) {
 	gotMetrics := collectMetrics(t, ch)
 	compareMetrics(t, gotMetrics, expectedRPKIVRFMetrics)
 }
+
+func TestProcessRPKIPrefixCount(t *testing.T) {
+	expected := map[string]float64{
+		"frr_rpki_roa_prefixes{afi=ipv4,vrf=default}": 452311,
+		"frr_rpki_roa_prefixes{afi=ipv6,vrf=default}": 98211,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processRPKIPrefixCount(ch, readTestFixture(t, "show_rpki_prefix_count.json"), "default", getRPKIDesc()); err != nil {
+		t.Errorf("error calling processRPKIPrefixCount: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestProcessRPKIConfiguration(t *testing.T) {
+	expected := map[string]float64{
+		"frr_rpki_enabled{vrf=default}":                 1,
+		"frr_rpki_cache_servers{vrf=default}":           2,
+		"frr_rpki_polling_period_seconds{vrf=default}":  3600,
+		"frr_rpki_retry_interval_seconds{vrf=default}":  600,
+		"frr_rpki_expire_interval_seconds{vrf=default}": 7200,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processRPKIConfiguration(ch, readTestFixture(t, "show_rpki_configuration.json"), "default", getRPKIDesc()); err != nil {
+		t.Errorf("error calling processRPKIConfiguration: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
@@ -0,0 +1,143 @@
+package collector
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var trackStateTransitions = kingpin.Flag("collector.state-transitions", "Remember the state of BGP peers, BFD peers and OSPF neighbors across scrapes to export state transition counters, the time of the last transition and the number of neighbors that vanished (default: disabled).").Default("False").Bool()
+
+// stateTracker remembers the state of the neighbors of a collector across scrapes, so flaps between scrapes
+// are not lost. Each scrape records the neighbors it sees in its own stateObservations, which collect merges
+// into the tracker under a single lock once the scrape completed, so concurrent scrapes cannot see each
+// other's partial observations. A nil stateTracker, as returned when --collector.state-transitions is not
+// set, ignores all calls.
+type stateTracker struct {
+	mu        sync.Mutex
+	collector string
+	now       func() time.Time
+	desc      map[string]*prometheus.Desc
+	neighbors map[string]*trackedNeighbor
+	vanished  float64
+	// started and merged are the sequence numbers of the latest scrape to start and to be merged.
+	started, merged uint64
+}
+
+type trackedNeighbor struct {
+	labels         []string
+	state          string
+	lastTransition time.Time
+	transitions    map[stateTransition]float64
+}
+
+type stateTransition struct {
+	from, to string
+}
+
+// stateObservations holds the state of each neighbor seen by a single scrape.
+type stateObservations struct {
+	seq    uint64
+	states map[string]observedState
+}
+
+type observedState struct {
+	labels []string
+	state  string
+}
+
+// newStateTracker returns a stateTracker of the named collector exporting its metrics as
+// frr_<subsystem>_<neighbor>_..., with labels identifying each neighbor.
+func newStateTracker(collector, subsystem, neighbor string, labels []string) *stateTracker {
+	if !*trackStateTransitions {
+		return nil
+	}
+
+	transitionLabels := append(append([]string{}, labels...), "from", "to")
+	return &stateTracker{
+		collector: collector,
+		now:       time.Now,
+		desc: map[string]*prometheus.Desc{
+			"transitions":    colPromDesc(subsystem, neighbor+"_state_transitions_total", "Number of state transitions of the "+neighbor+" observed by the exporter.", transitionLabels),
+			"lastTransition": colPromDesc(subsystem, neighbor+"_last_state_transition_timestamp_seconds", "Time the exporter last observed a state transition of the "+neighbor+", in seconds since the epoch.", labels),
+			"vanished":       colPromDesc(subsystem, neighbor+"s_vanished_total", "Number of times a "+neighbor+" seen in the previous scrape was missing from the next.", []string{"collector"}),
+		},
+		neighbors: make(map[string]*trackedNeighbor),
+	}
+}
+
+// begin starts the observations of a scrape.
+func (t *stateTracker) begin() *stateObservations {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.started++
+	return &stateObservations{seq: t.started, states: make(map[string]observedState)}
+}
+
+// observe records the state of the neighbor identified by labels.
+func (o *stateObservations) observe(state string, labels ...string) {
+	if o == nil {
+		return
+	}
+	o.states[strings.Join(labels, "\x00")] = observedState{labels: append([]string{}, labels...), state: state}
+}
+
+// collect merges the observations of a completed scrape into the tracker, forgetting the neighbors the scrape
+// did not see, and exports the transitions of every neighbor. The observations of a scrape that started before
+// the last merged one are outdated and are not merged.
+func (t *stateTracker) collect(ch chan<- prometheus.Metric, o *stateObservations) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if o != nil && o.seq > t.merged {
+		t.merge(o)
+	}
+
+	for _, n := range t.neighbors {
+		for transition, count := range n.transitions {
+			newCounter(ch, t.desc["transitions"], count, append(append([]string{}, n.labels...), transition.from, transition.to)...)
+		}
+		if !n.lastTransition.IsZero() {
+			newGauge(ch, t.desc["lastTransition"], float64(n.lastTransition.UnixNano())/1e9, n.labels...)
+		}
+	}
+	newCounter(ch, t.desc["vanished"], t.vanished, t.collector)
+}
+
+func (t *stateTracker) merge(o *stateObservations) {
+	t.merged = o.seq
+
+	for key := range t.neighbors {
+		if _, ok := o.states[key]; !ok {
+			t.vanished++
+			delete(t.neighbors, key)
+		}
+	}
+
+	for key, observed := range o.states {
+		n, ok := t.neighbors[key]
+		if !ok {
+			t.neighbors[key] = &trackedNeighbor{
+				labels:      observed.labels,
+				state:       observed.state,
+				transitions: make(map[stateTransition]float64),
+			}
+			continue
+		}
+		if n.state != observed.state {
+			n.transitions[stateTransition{from: n.state, to: observed.state}]++
+			n.lastTransition = t.now()
+			n.state = observed.state
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestStateTracker(t *testing.T) {
+	*trackStateTransitions = true
+	defer func() { *trackStateTransitions = false }()
+
+	tracker := newStateTracker("bfd", "bfd", "peer", []string{"peer", "vrf"})
+	now := time.Unix(1700000000, 0)
+	tracker.now = func() time.Time { return now }
+
+	scrape := func(observe func(*stateObservations)) map[string]float64 {
+		observations := tracker.begin()
+		observe(observations)
+		return collectStateTracker(t, tracker, observations)
+	}
+
+	// The first scrape only establishes the state of each peer.
+	got := scrape(func(o *stateObservations) {
+		o.observe("up", "10.0.0.1", "default")
+		o.observe("up", "10.0.0.2", "default")
+	})
+	compareMetrics(t, got, map[string]float64{
+		"frr_bfd_peers_vanished_total{collector=bfd}": 0,
+	})
+
+	now = now.Add(30 * time.Second)
+	got = scrape(func(o *stateObservations) {
+		o.observe("down", "10.0.0.1", "default")
+	})
+	compareMetrics(t, got, map[string]float64{
+		"frr_bfd_peer_state_transitions_total{from=up,peer=10.0.0.1,to=down,vrf=default}": 1,
+		"frr_bfd_peer_last_state_transition_timestamp_seconds{peer=10.0.0.1,vrf=default}": 1700000030,
+		"frr_bfd_peers_vanished_total{collector=bfd}":                                     1,
+	})
+
+	// 10.0.0.2 was forgotten when it vanished, so its return is not a transition.
+	now = now.Add(30 * time.Second)
+	got = scrape(func(o *stateObservations) {
+		o.observe("up", "10.0.0.1", "default")
+		o.observe("up", "10.0.0.2", "default")
+	})
+	compareMetrics(t, got, map[string]float64{
+		"frr_bfd_peer_state_transitions_total{from=up,peer=10.0.0.1,to=down,vrf=default}": 1,
+		"frr_bfd_peer_state_transitions_total{from=down,peer=10.0.0.1,to=up,vrf=default}": 1,
+		"frr_bfd_peer_last_state_transition_timestamp_seconds{peer=10.0.0.1,vrf=default}": 1700000060,
+		"frr_bfd_peers_vanished_total{collector=bfd}":                                     1,
+	})
+}
+
+func TestStateTrackerDisabled(t *testing.T) {
+	tracker := newStateTracker("bfd", "bfd", "peer", []string{"peer", "vrf"})
+	if tracker != nil {
+		t.Fatalf("expected no state tracker without --collector.state-transitions")
+	}
+
+	observations := tracker.begin()
+	observations.observe("up", "10.0.0.1", "default")
+	if got := collectStateTracker(t, tracker, observations); len(got) != 0 {
+		t.Errorf("expected no metrics from a disabled state tracker, got %v", got)
+	}
+}
+
+func TestStateTrackerConcurrentScrapes(t *testing.T) {
+	*trackStateTransitions = true
+	defer func() { *trackStateTransitions = false }()
+
+	tracker := newStateTracker("bfd", "bfd", "peer", []string{"peer", "vrf"})
+	now := time.Unix(1700000000, 0)
+	tracker.now = func() time.Time { return now }
+
+	first := tracker.begin()
+	first.observe("up", "10.0.0.1", "default")
+	first.observe("up", "10.0.0.2", "default")
+	collectStateTracker(t, tracker, first)
+
+	// Scrape a starts and observes its peers, then scrape b runs to completion before a collects.
+	now = now.Add(30 * time.Second)
+	a := tracker.begin()
+	b := tracker.begin()
+	a.observe("up", "10.0.0.1", "default")
+	a.observe("up", "10.0.0.2", "default")
+	b.observe("down", "10.0.0.1", "default")
+	b.observe("up", "10.0.0.2", "default")
+
+	expected := map[string]float64{
+		"frr_bfd_peer_state_transitions_total{from=up,peer=10.0.0.1,to=down,vrf=default}": 1,
+		"frr_bfd_peer_last_state_transition_timestamp_seconds{peer=10.0.0.1,vrf=default}": 1700000030,
+		"frr_bfd_peers_vanished_total{collector=bfd}":                                     0,
+	}
+	compareMetrics(t, collectStateTracker(t, tracker, b), expected)
+	// a started before b, so its observations are outdated and must neither undo b's transition nor
+	// count b's peers as vanished.
+	compareMetrics(t, collectStateTracker(t, tracker, a), expected)
+}
+
+func collectStateTracker(t *testing.T, tracker *stateTracker, observations *stateObservations) map[string]float64 {
+	t.Helper()
+	ch := make(chan prometheus.Metric, 16)
+	tracker.collect(ch, observations)
+	close(ch)
+	return collectMetrics(t, ch)
+}
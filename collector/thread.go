@@ -0,0 +1,211 @@
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	threadSubsystem = "thread"
+	threadTopTasks  = kingpin.Flag("collector.thread.top-tasks", "Only export the N tasks of each daemon with the highest total CPU time (default: 0, all tasks).").Default("0").Int()
+
+	threadDaemonHeader = regexp.MustCompile(`^Thread statistics for (\S+):`)
+	threadEventTypes   = map[string]string{
+		"R": "read",
+		"W": "write",
+		"T": "timer",
+		"E": "event",
+		"X": "execute",
+		"B": "background",
+	}
+)
+
+func init() {
+	registerCollector(threadSubsystem, disabledByDefault, NewThreadCollector)
+}
+
+type threadCollector struct {
+	logger       *slog.Logger
+	descriptions map[string]*prometheus.Desc
+}
+
+// NewThreadCollector collects per-daemon event loop task CPU usage, implemented as per the Collector interface.
+func NewThreadCollector(logger *slog.Logger) (Collector, error) {
+	return &threadCollector{logger: logger, descriptions: getThreadDesc()}, nil
+}
+
+func getThreadDesc() map[string]*prometheus.Desc {
+	labels := []string{"daemon", "task", "type"}
+
+	return map[string]*prometheus.Desc{
+		"active":      colPromDesc(threadSubsystem, "active", "Number of currently scheduled instances of the task.", labels),
+		"invocations": colPromDesc(threadSubsystem, "invocations_total", "Number of times the task has run.", labels),
+		"cpuTotal":    colPromDesc(threadSubsystem, "cpu_seconds_total", "Total CPU (user+system) time consumed by the task.", labels),
+		"cpuMax":      colPromDesc(threadSubsystem, "cpu_max_seconds", "Maximum CPU (user+system) time consumed by a single run of the task.", labels),
+		"wallAvg":     colPromDesc(threadSubsystem, "wall_avg_seconds", "Average wall-clock time consumed by a single run of the task.", labels),
+		"wallMax":     colPromDesc(threadSubsystem, "wall_max_seconds", "Maximum wall-clock time consumed by a single run of the task.", labels),
+	}
+}
+
+// Update implemented as per the Collector interface.
+func (c *threadCollector) Update(ch chan<- prometheus.Metric) error {
+	cmd := "show thread cpu"
+	outputs, execErr := executeAllDaemonsCommand(cmd)
+
+	var errs []error
+	for daemon, output := range outputs {
+		if err := processThreadCPU(ch, output, daemon, *threadTopTasks, c.descriptions); err != nil {
+			errs = append(errs, cmdOutputProcessError(cmd, string(output), err))
+		}
+	}
+	return errors.Join(append(errs, execErr)...)
+}
+
+type threadTask struct {
+	name        string
+	eventType   string
+	active      float64
+	invocations float64
+	cpuTotal    float64
+	cpuMax      float64
+	wallAvg     float64
+	wallMax     float64
+}
+
+// processThreadCPU parses the output of 'show thread cpu'. The output of a single daemon is
+// attributed to daemon, while output from vtysh contains a "Thread statistics for <daemon>:"
+// header before each daemon's statistics.
+func processThreadCPU(ch chan<- prometheus.Metric, output []byte, daemon string, topTasks int, threadDesc map[string]*prometheus.Desc) error {
+	tasks, err := parseThreadCPU(output, daemon)
+	if err != nil {
+		return err
+	}
+
+	for daemonName, daemonTasks := range tasks {
+		sort.Slice(daemonTasks, func(i, j int) bool {
+			return daemonTasks[i].cpuTotal > daemonTasks[j].cpuTotal
+		})
+		if topTasks > 0 && len(daemonTasks) > topTasks {
+			daemonTasks = daemonTasks[:topTasks]
+		}
+
+		for _, task := range daemonTasks {
+			labels := []string{daemonName, task.name, task.eventType}
+			newGauge(ch, threadDesc["active"], task.active, labels...)
+			newCounter(ch, threadDesc["invocations"], task.invocations, labels...)
+			newCounter(ch, threadDesc["cpuTotal"], task.cpuTotal, labels...)
+			newGauge(ch, threadDesc["cpuMax"], task.cpuMax, labels...)
+			newGauge(ch, threadDesc["wallAvg"], task.wallAvg, labels...)
+			newGauge(ch, threadDesc["wallMax"], task.wallMax, labels...)
+		}
+	}
+	return nil
+}
+
+func parseThreadCPU(output []byte, daemon string) (map[string][]*threadTask, error) {
+	tasks := make(map[string][]*threadTask)
+	// The same task can be listed under more than one pthread, in which case the statistics are
+	// aggregated.
+	index := make(map[[3]string]*threadTask)
+	inTotals := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if match := threadDaemonHeader.FindStringSubmatch(line); match != nil {
+			daemon = match[1]
+			inTotals = false
+			continue
+		}
+		if strings.HasPrefix(line, "Showing statistics for pthread") {
+			inTotals = false
+			continue
+		}
+		// The totals section repeats the statistics of all pthreads.
+		if strings.HasPrefix(line, "Total thread statistics") {
+			inTotals = true
+			continue
+		}
+		if inTotals {
+			continue
+		}
+
+		task, ok := parseThreadCPULine(line)
+		if !ok {
+			continue
+		}
+
+		key := [3]string{daemon, task.name, task.eventType}
+		if existing, ok := index[key]; ok {
+			if invocations := existing.invocations + task.invocations; invocations > 0 {
+				existing.wallAvg = (existing.wallAvg*existing.invocations + task.wallAvg*task.invocations) / invocations
+			}
+			existing.active += task.active
+			existing.invocations += task.invocations
+			existing.cpuTotal += task.cpuTotal
+			existing.cpuMax = max(existing.cpuMax, task.cpuMax)
+			existing.wallMax = max(existing.wallMax, task.wallMax)
+			continue
+		}
+		index[key] = task
+		tasks[daemon] = append(tasks[daemon], task)
+	}
+	return tasks, scanner.Err()
+}
+
+// parseThreadCPULine parses a single task line, for example:
+//
+//	Active   Runtime(ms)   Invoked Avg uSec Max uSecs Avg uSec Max uSecs  CPU_Warn Wall_Warn Starv_Warn Type  Thread
+//	    0         19.160       306       62       591       67       617         0         0          0    T    bgp_start_timer
+//
+// Older FRR versions do not include the warning columns.
+func parseThreadCPULine(line string) (*threadTask, bool) {
+	fields := strings.Fields(line)
+
+	var numbers []float64
+	for _, field := range fields {
+		n, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			break
+		}
+		numbers = append(numbers, n)
+	}
+	rest := fields[len(numbers):]
+	if len(numbers) < 7 || len(rest) < 2 {
+		return nil, false
+	}
+
+	// The type column has a letter per event type of the task, which are separated by spaces or not depending on
+	// the types present, e.g. "R   T" or "RW".
+	var eventTypes []string
+	for _, token := range rest[:len(rest)-1] {
+		for _, t := range token {
+			eventType, ok := threadEventTypes[string(t)]
+			if !ok {
+				return nil, false
+			}
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+
+	return &threadTask{
+		name:        rest[len(rest)-1],
+		eventType:   strings.Join(eventTypes, ","),
+		active:      numbers[0],
+		invocations: numbers[2],
+		cpuTotal:    numbers[1] / 1e3,
+		cpuMax:      numbers[4] / 1e6,
+		wallAvg:     numbers[5] / 1e6,
+		wallMax:     numbers[6] / 1e6,
+	}, true
+}
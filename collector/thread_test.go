@@ -0,0 +1,97 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestProcessThreadCPU(t *testing.T) {
+	expected := map[string]float64{
+		"frr_thread_active{daemon=bgpd,task=bgp_process_packet,type=timer}":            0,
+		"frr_thread_invocations_total{daemon=bgpd,task=bgp_process_packet,type=timer}": 4000,
+		"frr_thread_cpu_seconds_total{daemon=bgpd,task=bgp_process_packet,type=timer}": 1.52025,
+		"frr_thread_cpu_max_seconds{daemon=bgpd,task=bgp_process_packet,type=timer}":   0.009,
+		"frr_thread_wall_avg_seconds{daemon=bgpd,task=bgp_process_packet,type=timer}":  0.0004,
+		"frr_thread_wall_max_seconds{daemon=bgpd,task=bgp_process_packet,type=timer}":  0.0095,
+		"frr_thread_active{daemon=bgpd,task=bgp_process_reads,type=read}":              0,
+		"frr_thread_invocations_total{daemon=bgpd,task=bgp_process_reads,type=read}":   2000,
+		"frr_thread_cpu_seconds_total{daemon=bgpd,task=bgp_process_reads,type=read}":   0.48,
+		"frr_thread_cpu_max_seconds{daemon=bgpd,task=bgp_process_reads,type=read}":     0.004,
+		"frr_thread_wall_avg_seconds{daemon=bgpd,task=bgp_process_reads,type=read}":    0.00025,
+		"frr_thread_wall_max_seconds{daemon=bgpd,task=bgp_process_reads,type=read}":    0.0042,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processThreadCPU(ch, readTestFixture(t, "show_thread_cpu.txt"), "bgpd", 2, getThreadDesc()); err != nil {
+		t.Errorf("error calling processThreadCPU: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestProcessThreadCPUVtysh(t *testing.T) {
+	expected := map[string]float64{
+		"frr_thread_active{daemon=zebra,task=work_queue_run,type=event}":             0,
+		"frr_thread_invocations_total{daemon=zebra,task=work_queue_run,type=event}":  3000,
+		"frr_thread_cpu_seconds_total{daemon=zebra,task=work_queue_run,type=event}":  0.9,
+		"frr_thread_cpu_max_seconds{daemon=zebra,task=work_queue_run,type=event}":    0.005,
+		"frr_thread_wall_avg_seconds{daemon=zebra,task=work_queue_run,type=event}":   0.00032,
+		"frr_thread_wall_max_seconds{daemon=zebra,task=work_queue_run,type=event}":   0.0052,
+		"frr_thread_active{daemon=zebra,task=kernel_read,type=read}":                 0,
+		"frr_thread_invocations_total{daemon=zebra,task=kernel_read,type=read}":      200,
+		"frr_thread_cpu_seconds_total{daemon=zebra,task=kernel_read,type=read}":      0.04,
+		"frr_thread_cpu_max_seconds{daemon=zebra,task=kernel_read,type=read}":        0.0007,
+		"frr_thread_wall_avg_seconds{daemon=zebra,task=kernel_read,type=read}":       0.00021,
+		"frr_thread_wall_max_seconds{daemon=zebra,task=kernel_read,type=read}":       0.00075,
+		"frr_thread_active{daemon=zebra,task=zserv_read,type=read,write}":            0,
+		"frr_thread_invocations_total{daemon=zebra,task=zserv_read,type=read,write}": 100,
+		"frr_thread_cpu_seconds_total{daemon=zebra,task=zserv_read,type=read,write}": 0.02,
+		"frr_thread_cpu_max_seconds{daemon=zebra,task=zserv_read,type=read,write}":   0.0004,
+		"frr_thread_wall_avg_seconds{daemon=zebra,task=zserv_read,type=read,write}":  0.00022,
+		"frr_thread_wall_max_seconds{daemon=zebra,task=zserv_read,type=read,write}":  0.00045,
+		"frr_thread_active{daemon=staticd,task=zclient_read,type=read}":              0,
+		"frr_thread_invocations_total{daemon=staticd,task=zclient_read,type=read}":   15,
+		"frr_thread_cpu_seconds_total{daemon=staticd,task=zclient_read,type=read}":   0.0015,
+		"frr_thread_cpu_max_seconds{daemon=staticd,task=zclient_read,type=read}":     0.0002,
+		"frr_thread_wall_avg_seconds{daemon=staticd,task=zclient_read,type=read}":    0.00011,
+		"frr_thread_wall_max_seconds{daemon=staticd,task=zclient_read,type=read}":    0.00025,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processThreadCPU(ch, readTestFixture(t, "show_thread_cpu_vtysh.txt"), "", 0, getThreadDesc()); err != nil {
+		t.Errorf("error calling processThreadCPU: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestParseThreadCPUMergesPthreads(t *testing.T) {
+	tasks, err := parseThreadCPU(readTestFixture(t, "show_thread_cpu.txt"), "bgpd")
+	if err != nil {
+		t.Fatalf("error calling parseThreadCPU: %s", err)
+	}
+
+	if len(tasks["bgpd"]) != 5 {
+		t.Fatalf("expected 5 bgpd tasks, got %d", len(tasks["bgpd"]))
+	}
+
+	for _, task := range tasks["bgpd"] {
+		switch task.name {
+		case "bgp_start_timer":
+			if task.active != 1 || task.invocations != 356 || task.cpuMax != 0.0006 || task.wallMax != 0.0008 {
+				t.Errorf("unexpected merged bgp_start_timer task: %+v", *task)
+			}
+			// The average wall-clock time is weighted by the invocations under each pthread.
+			if want := (67e-6*306 + 170e-6*50) / 356; task.wallAvg != want {
+				t.Errorf("expected merged bgp_start_timer wall average %v, got %v", want, task.wallAvg)
+			}
+		case "zclient_read":
+			if task.eventType != "read,timer" {
+				t.Errorf("expected zclient_read type read,timer, got %s", task.eventType)
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package collector
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var vrfSubsystem = "vrf"
+
+func init() {
+	registerCollector(vrfSubsystem, disabledByDefault, NewVRFCollector)
+}
+
+type vrfCollector struct {
+	logger       *slog.Logger
+	descriptions map[string]*prometheus.Desc
+}
+
+// NewVRFCollector collects VRF inventory metrics, implemented as per the Collector interface.
+func NewVRFCollector(logger *slog.Logger) (Collector, error) {
+	return &vrfCollector{logger: logger, descriptions: getVRFDesc()}, nil
+}
+
+func getVRFDesc() map[string]*prometheus.Desc {
+	labels := []string{"vrf"}
+
+	return map[string]*prometheus.Desc{
+		"info":       colPromDesc(vrfSubsystem, "info", "VRF along with its VRF ID, kernel table ID and L3VNI. Labels are empty when unknown.", []string{"vrf", "id", "table", "l3vni"}),
+		"active":     colPromDesc(vrfSubsystem, "active", "Whether the VRF is active (1 = active, 0 = inactive).", labels),
+		"interfaces": colPromDesc(vrfSubsystem, "interfaces", "Number of interfaces in the VRF.", labels),
+	}
+}
+
+// Update implemented as per the Collector interface.
+func (c *vrfCollector) Update(ch chan<- prometheus.Metric) error {
+	cmdVRF := "show vrf"
+	cmdVNI := "show vrf vni json"
+	cmdInterfaces := "show interface vrf all json"
+
+	outputVRF, err := executeZebraCommand(cmdVRF)
+	if err != nil {
+		return err
+	}
+	jsonVNI, err := executeZebraCommand(cmdVNI)
+	if err != nil {
+		return err
+	}
+	jsonInterfaces, err := executeZebraCommand(cmdInterfaces)
+	if err != nil {
+		return err
+	}
+
+	l3vnis, err := parseVRFVNIs(jsonVNI)
+	if err != nil {
+		return cmdOutputProcessError(cmdVNI, string(jsonVNI), err)
+	}
+	processVRFs(ch, outputVRF, l3vnis, c.descriptions)
+
+	if err := processVRFInterfaces(ch, jsonInterfaces, c.descriptions); err != nil {
+		return cmdOutputProcessError(cmdInterfaces, string(jsonInterfaces), err)
+	}
+	return nil
+}
+
+// processVRFs exports the default VRF and the VRFs of 'show vrf'.
+func processVRFs(ch chan<- prometheus.Metric, output []byte, l3vnis map[string]string, vrfDesc map[string]*prometheus.Desc) {
+	for _, vrf := range parseVRFDetails(output) {
+		newGauge(ch, vrfDesc["info"], 1, vrf.name, vrf.id, vrf.table, l3vnis[vrf.name])
+		newGauge(ch, vrfDesc["active"], boolToFloat(vrf.active), vrf.name)
+	}
+}
+
+// parseVRFVNIs returns the L3VNI of each VRF in the output of 'show vrf vni json'.
+func parseVRFVNIs(jsonVNI []byte) (map[string]string, error) {
+	l3vnis := make(map[string]string)
+	// zebra returns no output at all when EVPN is not enabled.
+	if len(strings.TrimSpace(string(jsonVNI))) == 0 {
+		return l3vnis, nil
+	}
+
+	var vrfVNIs vrfVNIList
+	if err := json.Unmarshal(jsonVNI, &vrfVNIs); err != nil {
+		return nil, err
+	}
+	for _, v := range vrfVNIs.VRFs {
+		l3vnis[v.VRF] = strconv.FormatUint(uint64(v.VNI), 10)
+	}
+	return l3vnis, nil
+}
+
+func processVRFInterfaces(ch chan<- prometheus.Metric, jsonInterfaces []byte, vrfDesc map[string]*prometheus.Desc) error {
+	var interfaces map[string]zebraInterface
+	if err := json.Unmarshal(jsonInterfaces, &interfaces); err != nil {
+		return err
+	}
+
+	counts := make(map[string]float64)
+	for _, iface := range interfaces {
+		counts[iface.VrfName]++
+	}
+	for vrf, count := range counts {
+		newGauge(ch, vrfDesc["interfaces"], count, vrf)
+	}
+	return nil
+}
+
+type vrfVNIList struct {
+	VRFs []vrfVNI `json:"vrfs"`
+}
+
+type vrfVNI struct {
+	VRF string `json:"vrf"`
+	VNI uint32 `json:"vni"`
+}
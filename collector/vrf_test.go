@@ -0,0 +1,46 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestProcessVRFs(t *testing.T) {
+	expected := map[string]float64{
+		"frr_vrf_info{id=0,l3vni=,table=254,vrf=default}":   1,
+		"frr_vrf_active{vrf=default}":                       1,
+		"frr_vrf_info{id=,l3vni=,table=,vrf=blue}":          1,
+		"frr_vrf_active{vrf=blue}":                          0,
+		"frr_vrf_info{id=11,l3vni=,table=,vrf=green}":       1,
+		"frr_vrf_active{vrf=green}":                         1,
+		"frr_vrf_info{id=10,l3vni=4001,table=1001,vrf=red}": 1,
+		"frr_vrf_active{vrf=red}":                           1,
+	}
+
+	l3vnis, err := parseVRFVNIs(readTestFixture(t, "show_vrf_vni.json"))
+	if err != nil {
+		t.Fatalf("error calling parseVRFVNIs: %s", err)
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	processVRFs(ch, readTestFixture(t, "show_vrf_detail.txt"), l3vnis, getVRFDesc())
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestProcessVRFInterfaces(t *testing.T) {
+	expected := map[string]float64{
+		"frr_vrf_interfaces{vrf=default}": 3,
+		"frr_vrf_interfaces{vrf=red}":     1,
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processVRFInterfaces(ch, readTestFixture(t, "show_interface_vrf_all.json"), getVRFDesc()); err != nil {
+		t.Errorf("error calling processVRFInterfaces: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
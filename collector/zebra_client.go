@@ -0,0 +1,150 @@
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	zebraClientSubsystem = "zebra_client"
+
+	zebraClientHeader = regexp.MustCompile(`^Client: (\S+)(?: Instance: (\d+))?(?: \[(\d+)\])?`)
+
+	// The columns of the route operation table of 'show zebra client'.
+	zebraClientOperations = []string{"add", "update", "delete"}
+)
+
+func init() {
+	registerCollector(zebraClientSubsystem, disabledByDefault, NewZebraClientCollector)
+}
+
+type zebraClientCollector struct {
+	logger       *slog.Logger
+	descriptions map[string]*prometheus.Desc
+}
+
+// NewZebraClientCollector collects per zebra client statistics, implemented as per the Collector interface.
+func NewZebraClientCollector(logger *slog.Logger) (Collector, error) {
+	return &zebraClientCollector{logger: logger, descriptions: getZebraClientDesc()}, nil
+}
+
+func getZebraClientDesc() map[string]*prometheus.Desc {
+	clientLabels := []string{"client", "instance", "session"}
+	operationLabels := append(clientLabels, "type", "operation")
+
+	return map[string]*prometheus.Desc{
+		"operations": colPromDesc(zebraClientSubsystem, "operations_total", "Number of add, update and delete messages exchanged with the zebra client per message type.", operationLabels),
+		"errors":     colPromDesc(zebraClientSubsystem, "errors_total", "Number of errors processing messages from the zebra client.", clientLabels),
+		"uptime":     colPromDesc(zebraClientSubsystem, "connection_uptime_seconds", "How long the zebra client has been connected.", clientLabels),
+	}
+}
+
+// Update implemented as per the Collector interface.
+func (c *zebraClientCollector) Update(ch chan<- prometheus.Metric) error {
+	cmd := "show zebra client"
+	output, err := executeZebraCommand(cmd)
+	if err != nil {
+		return err
+	}
+	if err := processZebraClients(ch, output, c.logger, c.descriptions); err != nil {
+		return cmdOutputProcessError(cmd, string(output), err)
+	}
+	return nil
+}
+
+// processZebraClients parses the output of 'show zebra client', for example:
+//
+//	Client: bgp Instance: 1
+//	------------------------
+//	FD: 25
+//	Connect Time: 00:10:21
+//	...
+//	Type        Add         Update          Del
+//	==================================================
+//	IPv4        100         3           5
+//	BFD peer    2           0           0
+//	...
+//	Errors: 0
+func processZebraClients(ch chan<- prometheus.Metric, output []byte, logger *slog.Logger, zebraClientDesc map[string]*prometheus.Desc) error {
+	var labels []string
+	inTable := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if match := zebraClientHeader.FindStringSubmatch(line); match != nil {
+			instance, session := match[2], match[3]
+			if instance == "" {
+				instance = "0"
+			}
+			if session == "" {
+				session = "0"
+			}
+			labels = []string{match[1], instance, session}
+			inTable = false
+			continue
+		}
+		if labels == nil {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "Connect Time:"):
+			raw := strings.TrimSpace(strings.TrimPrefix(line, "Connect Time:"))
+			uptime, err := parseZebraTime(raw)
+			if err != nil {
+				logger.Error("cannot parse zebra client connect time", "client", labels[0], "connect_time", raw, "err", err)
+				continue
+			}
+			newGauge(ch, zebraClientDesc["uptime"], float64(uptime), labels...)
+		case strings.HasPrefix(line, "Errors:"):
+			inTable = false
+			if errs, err := strconv.ParseUint(strings.TrimSpace(strings.TrimPrefix(line, "Errors:")), 10, 64); err == nil {
+				newCounter(ch, zebraClientDesc["errors"], float64(errs), labels...)
+			}
+		case strings.HasPrefix(line, "Type ") && strings.HasSuffix(line, "Del"):
+			inTable = true
+		case inTable:
+			fields := strings.Fields(line)
+			if len(fields) < len(zebraClientOperations)+1 {
+				continue
+			}
+			msgType := strings.Join(fields[:len(fields)-len(zebraClientOperations)], " ")
+			for i, operation := range zebraClientOperations {
+				value, err := strconv.ParseUint(fields[len(fields)-len(zebraClientOperations)+i], 10, 64)
+				if err != nil {
+					continue
+				}
+				newCounter(ch, zebraClientDesc["operations"], float64(value), append(labels, msgType, operation)...)
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// parseZebraTime parses a time formatted by zebra, which is "HH:MM:SS" for less than a day,
+// "XdXXhXXm" for less than a week and "XXwXdXXh" otherwise, into seconds.
+func parseZebraTime(st string) (uint64, error) {
+	var w, d, h, m uint64
+	if strings.Contains(st, ":") {
+		return parseHMS(st)
+	}
+	if strings.Contains(st, "w") {
+		if _, err := fmt.Sscanf(st, "%dw%dd%dh", &w, &d, &h); err != nil {
+			return 0, err
+		}
+		return ((w*7+d)*24 + h) * 3600, nil
+	}
+	if _, err := fmt.Sscanf(st, "%dd%dh%dm", &d, &h, &m); err != nil {
+		return 0, err
+	}
+	return (d*24+h)*3600 + m*60, nil
+}
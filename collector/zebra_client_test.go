@@ -0,0 +1,80 @@
+package collector
+
+import (
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestProcessZebraClients(t *testing.T) {
+	expected := map[string]float64{
+		"frr_zebra_client_connection_uptime_seconds{client=bgp,instance=0,session=0}":  2*86400 + 3*3600 + 4*60,
+		"frr_zebra_client_errors_total{client=bgp,instance=0,session=0}":               1,
+		"frr_zebra_client_connection_uptime_seconds{client=ospf,instance=2,session=3}": 3723,
+		"frr_zebra_client_errors_total{client=ospf,instance=2,session=3}":              0,
+	}
+	bgpOperations := map[string][3]float64{
+		"IPv4":      {1500, 20, 30},
+		"IPv6":      {400, 0, 12},
+		"Redist:v4": {80, 0, 2},
+		"Redist:v6": {0, 0, 0},
+		"VRF":       {1, 0, 0},
+		"Connected": {6, 0, 0},
+		"Interface": {6, 0, 1},
+		"Intf Addr": {12, 0, 0},
+		"BFD peer":  {0, 0, 0},
+		"NHT v4":    {45, 0, 3},
+		"NHT v6":    {0, 0, 0},
+		"VxLAN SG":  {0, 0, 0},
+		"VNI":       {0, 0, 0},
+		"L3-VNI":    {0, 0, 0},
+		"MAC-IP":    {0, 0, 0},
+		"ES":        {0, 0, 0},
+		"ES-EVI":    {0, 0, 0},
+	}
+	ospfOperations := map[string][3]float64{
+		"IPv4": {10, 0, 1},
+		"IPv6": {0, 0, 0},
+	}
+	for _, client := range []struct {
+		name, instance, session string
+		operations              map[string][3]float64
+	}{
+		{name: "bgp", instance: "0", session: "0", operations: bgpOperations},
+		{name: "ospf", instance: "2", session: "3", operations: ospfOperations},
+	} {
+		for msgType, values := range client.operations {
+			for i, operation := range zebraClientOperations {
+				key := fmt.Sprintf("frr_zebra_client_operations_total{client=%s,instance=%s,operation=%s,session=%s,type=%s}", client.name, client.instance, operation, client.session, msgType)
+				expected[key] = values[i]
+			}
+		}
+	}
+
+	ch := make(chan prometheus.Metric, 1024)
+	if err := processZebraClients(ch, readTestFixture(t, "show_zebra_client.txt"), slog.New(slog.DiscardHandler), getZebraClientDesc()); err != nil {
+		t.Errorf("error calling processZebraClients: %s", err)
+	}
+	close(ch)
+
+	compareMetrics(t, collectMetrics(t, ch), expected)
+}
+
+func TestParseZebraTime(t *testing.T) {
+	for input, expected := range map[string]uint64{
+		"00:10:21": 621,
+		"2d03h04m": 2*86400 + 3*3600 + 4*60,
+		"12w3d05h": (12*7+3)*86400 + 5*3600,
+	} {
+		got, err := parseZebraTime(input)
+		if err != nil {
+			t.Errorf("error parsing %q: %s", input, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("parseZebraTime(%q) expected %d got %d", input, expected, got)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -25,6 +26,10 @@ func (c Connection) ExecBGPCmd(cmd string) ([]byte, error) {
 	return executeCmd(filepath.Join(c.dirPath, "bgpd.vty"), cmd, c.timeout)
 }
 
+func (c Connection) ExecFabricdCmd(cmd string) ([]byte, error) {
+	return executeCmd(filepath.Join(c.dirPath, "fabricd.vty"), cmd, c.timeout)
+}
+
 func (c Connection) ExecOSPFCmd(cmd string) ([]byte, error) {
 	return executeCmd(filepath.Join(c.dirPath, "ospfd.vty"), cmd, c.timeout)
 }
@@ -45,6 +50,25 @@ func (c Connection) ExecZebraCmd(cmd string) ([]byte, error) {
 	return executeCmd(filepath.Join(c.dirPath, "zebra.vty"), cmd, c.timeout)
 }
 
+// ExecDaemonCmd executes cmd against the Unix socket of the named daemon, e.g. bgpd or ospfd-1.
+func (c Connection) ExecDaemonCmd(daemon, cmd string) ([]byte, error) {
+	return executeCmd(filepath.Join(c.dirPath, daemon+".vty"), cmd, c.timeout)
+}
+
+// Daemons returns the name of each daemon with a Unix socket in the socket directory.
+func (c Connection) Daemons() ([]string, error) {
+	sockets, err := filepath.Glob(filepath.Join(c.dirPath, "*.vty"))
+	if err != nil {
+		return nil, err
+	}
+
+	daemons := make([]string, 0, len(sockets))
+	for _, socket := range sockets {
+		daemons = append(daemons, strings.TrimSuffix(filepath.Base(socket), ".vty"))
+	}
+	return daemons, nil
+}
+
 func executeCmd(socketPath, cmd string, timeout time.Duration) ([]byte, error) {
 	var response bytes.Buffer
 
@@ -4,6 +4,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -48,6 +49,25 @@ func TestExecuteCmdWithLargeOutput(t *testing.T) {
 	}
 }
 
+func TestDaemons(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"bgpd.vty", "zebra.vty", "ospfd-1.vty", "bgpd.pid"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatalf("cannot create %s: %v", name, err)
+		}
+	}
+
+	daemons, err := NewConnection(dir, time.Second).Daemons()
+	if err != nil {
+		t.Fatalf("Daemons returned error: %v", err)
+	}
+
+	expected := []string{"bgpd", "ospfd-1", "zebra"}
+	if !reflect.DeepEqual(daemons, expected) {
+		t.Fatalf("Daemons expected %v, got %v", expected, daemons)
+	}
+}
+
 func mockSocket(socketPath string, socketData string) {
 	// Simple mock of FRR Unix socket
 	l, err := net.Listen("unix", socketPath)